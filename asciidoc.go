@@ -0,0 +1,180 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+
+	"k8s.io/gengo/v2/types"
+)
+
+var (
+	mdFence        = regexp.MustCompile("^\\s*(```|~~~)\\s*([\\w+-]*)\\s*$")
+	mdHeading      = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	mdBulletItem   = regexp.MustCompile(`^(\s*)[-*+]\s+(.*)$`)
+	mdOrderedItem  = regexp.MustCompile(`^(\s*)\d+[.)]\s+(.*)$`)
+	mdIndentedCode = regexp.MustCompile(`^(\t|    )`)
+	mdCodeSpan     = regexp.MustCompile("`+[^`]*`+")
+	mdLink         = regexp.MustCompile(`\[([^\]]*)\]\(([^)\s]+)\)`)
+	mdAutoLink     = regexp.MustCompile(`<((?:https?|mailto):[^>\s]+)>`)
+	mdStrong       = regexp.MustCompile(`\*\*([^*]+)\*\*|__([^_]+)__`)
+	mdEmphasis     = regexp.MustCompile(`(^|[^\w*])\*([^*\s][^*]*?)\*`)
+)
+
+// asciidocLiteral formats s as literal monospace text.
+func asciidocLiteral(s string) string {
+	if strings.Contains(s, "+") {
+		return "`++" + s + "++`"
+	}
+	return "`+" + s + "+`"
+}
+
+// asciidocTypeLink returns the AsciiDoc markup for a reference to type t,
+// given the link to it: a cross-reference for local types, a link macro for
+// recognized external types, or the plain display name otherwise.
+func asciidocTypeLink(t *types.Type, link string, c generatorConfig, typePkgMap map[*types.Type]*apiPackage) string {
+	name := asciidocLiteral(typeDisplayName(t, c, typePkgMap))
+
+	switch {
+	case strings.HasPrefix(link, "#"):
+		return "<<" + sanitizeAnchorID(strings.TrimPrefix(link, "#")) + "," + name + ">>"
+	case isLocalType(t, typePkgMap):
+		// a type on another page
+		page, anchor, _ := strings.Cut(link, "#")
+		return "xref:" + page + "#" + sanitizeAnchorID(anchor) + "[" + name + "]"
+	case link != "":
+		return "link:" + link + "[" + name + "]"
+	}
+	return name
+}
+
+// renderCommentsAsciiDoc returns the comment lines as AsciiDoc source,
+// converting them from Markdown unless markdown is false.
+func renderCommentsAsciiDoc(s []string, markdown bool) string {
+	s = filterCommentTags(s)
+	doc := strings.TrimSpace(strings.Join(s, "\n"))
+
+	if markdown {
+		return markdownToAsciiDoc(doc)
+	}
+	return doc
+}
+
+// asciidocText returns the plain text s as an AsciiDoc passthrough, so that
+// only the HTML special characters in it are escaped.
+func asciidocText(s string) string {
+	return "pass:c[" + strings.Replace(s, "]", `\]`, -1) + "]"
+}
+
+// asciidocTableCell escapes s so it can be used as the content of an
+// AsciiDoc table cell.
+func asciidocTableCell(s string) string {
+	return strings.Replace(strings.TrimSpace(s), "|", `\|`, -1)
+}
+
+// markdownToAsciiDoc converts the subset of Markdown that shows up in godoc
+// comments (paragraphs, headings, lists, code and links) to AsciiDoc.
+func markdownToAsciiDoc(s string) string {
+	var out []string
+	var fence string  // delimiter of the open fenced code block
+	var indented bool // whether an indented code block is open
+	prevBlank := true
+
+	for _, line := range strings.Split(s, "\n") {
+		if fence != "" {
+			if m := mdFence.FindStringSubmatch(line); m != nil && m[1] == fence {
+				out = append(out, "----")
+				fence = ""
+			} else {
+				out = append(out, line)
+			}
+			continue
+		}
+		if indented {
+			if mdIndentedCode.MatchString(line) || strings.TrimSpace(line) == "" {
+				out = append(out, strings.TrimPrefix(strings.TrimPrefix(line, "\t"), "    "))
+				continue
+			}
+			out = closeBlock(out, "....")
+			indented = false
+		}
+
+		switch {
+		case mdFence.MatchString(line):
+			m := mdFence.FindStringSubmatch(line)
+			fence = m[1]
+			if m[2] != "" {
+				out = append(out, "[source,"+m[2]+"]")
+			}
+			out = append(out, "----")
+		case prevBlank && mdIndentedCode.MatchString(line) && !mdBulletItem.MatchString(line) && !mdOrderedItem.MatchString(line):
+			indented = true
+			out = append(out, "....", strings.TrimPrefix(strings.TrimPrefix(line, "\t"), "    "))
+		case mdHeading.MatchString(line):
+			m := mdHeading.FindStringSubmatch(line)
+			// headings in comments must not interfere with the sections
+			// generated for packages and types.
+			out = append(out, "[discrete]", strings.Repeat("=", len(m[1])+3)+" "+asciidocInline(m[2]))
+		case mdBulletItem.MatchString(line):
+			m := mdBulletItem.FindStringSubmatch(line)
+			out = append(out, strings.Repeat("*", listDepth(m[1]))+" "+asciidocInline(m[2]))
+		case mdOrderedItem.MatchString(line):
+			m := mdOrderedItem.FindStringSubmatch(line)
+			out = append(out, strings.Repeat(".", listDepth(m[1]))+" "+asciidocInline(m[2]))
+		default:
+			out = append(out, asciidocInline(line))
+		}
+		prevBlank = strings.TrimSpace(line) == ""
+	}
+	if fence != "" {
+		out = append(out, "----")
+	}
+	if indented {
+		out = closeBlock(out, "....")
+	}
+	return strings.Join(out, "\n")
+}
+
+// closeBlock terminates a delimited block opened for indented code, keeping
+// the blank lines that followed the code outside of it.
+func closeBlock(lines []string, delimiter string) []string {
+	var trailing int
+	for i := len(lines) - 1; i >= 0 && strings.TrimSpace(lines[i]) == ""; i-- {
+		trailing++
+	}
+	lines = append(lines[:len(lines)-trailing], delimiter)
+	for i := 0; i < trailing; i++ {
+		lines = append(lines, "")
+	}
+	return lines
+}
+
+func listDepth(indent string) int {
+	return len(strings.Replace(indent, "\t", "  ", -1))/2 + 1
+}
+
+// asciidocInline converts the inline Markdown markup in s, leaving code spans
+// untouched.
+func asciidocInline(s string) string {
+	var b strings.Builder
+	last := 0
+	for _, loc := range mdCodeSpan.FindAllStringIndex(s, -1) {
+		b.WriteString(asciidocInlineText(s[last:loc[0]]))
+		b.WriteString(asciidocLiteral(strings.TrimSpace(strings.Trim(s[loc[0]:loc[1]], "`"))))
+		last = loc[1]
+	}
+	b.WriteString(asciidocInlineText(s[last:]))
+	return b.String()
+}
+
+func asciidocInlineText(s string) string {
+	s = mdAutoLink.ReplaceAllString(s, "$1")
+	s = mdLink.ReplaceAllStringFunc(s, func(v string) string {
+		m := mdLink.FindStringSubmatch(v)
+		return "link:" + m[2] + "[" + strings.Replace(m[1], "]", `\]`, -1) + "]"
+	})
+	// strong emphasis is swapped for a placeholder first, otherwise its "*"
+	// markers would be picked up as emphasis.
+	s = mdStrong.ReplaceAllString(s, "\x00$1$2\x00")
+	s = mdEmphasis.ReplaceAllString(s, "${1}_${2}_")
+	return strings.Replace(s, "\x00", "*", -1)
+}
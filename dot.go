@@ -0,0 +1,109 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"k8s.io/gengo/v2/types"
+)
+
+// renderDOT writes the graph of the types of the API packages and the
+// external types they refer to in the Graphviz DOT language. The types of
+// each API package are clustered together, and each field referring to
+// another type is an edge labeled with the name of the field.
+func renderDOT(w io.Writer, pkgs []*apiPackage, config generatorConfig) error {
+	typePkgMap := extractTypeToPackageMap(pkgs)
+	nodeID := func(t *types.Type) string {
+		if isLocalType(t, typePkgMap) {
+			return anchorIDForLocalType(t, typePkgMap)
+		}
+		return t.Name.String()
+	}
+
+	visible := make(map[*types.Type]bool)
+	for _, pkg := range pkgs {
+		for _, t := range visibleTypes(pkg.Types, config) {
+			visible[t] = true
+		}
+	}
+
+	var b bytes.Buffer
+	var edges, external []string
+	seen := make(map[*types.Type]bool)
+	b.WriteString("digraph api {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=box, fontname=\"Helvetica\"];\n")
+	b.WriteString("  edge [fontname=\"Helvetica\", fontsize=10];\n")
+	for _, pkg := range pkgs {
+		fmt.Fprintf(&b, "\n  subgraph %s {\n", dotID("cluster_"+pkg.identifier()))
+		fmt.Fprintf(&b, "    label=%s;\n", dotID(pkg.identifier()))
+		for _, t := range visibleTypes(sortTypes(pkg.Types), config) {
+			attrs := "label=" + dotID(t.Name.Name)
+			if isExportedType(t) {
+				attrs += ", style=bold"
+			}
+			fmt.Fprintf(&b, "    %s [%s];\n", dotID(nodeID(t)), attrs)
+
+			for _, m := range t.Members {
+				tag := parseJSONTag(m)
+				if tag.skip || hiddenMember(m, config) {
+					continue
+				}
+				target := tryDereference(m.Type)
+				if target.Kind == types.Builtin || (isLocalType(target, typePkgMap) && !visible[target]) {
+					continue
+				}
+				label := tag.name
+				if tag.inline {
+					label = "(inline)"
+				}
+				attrs := "label=" + dotID(label)
+				// references across API packages are the coupling the graph
+				// is meant to show
+				if p := typePkgMap[target]; p != nil && p != pkg {
+					attrs += ", style=bold, color=red"
+				}
+				edges = append(edges, fmt.Sprintf("  %s -> %s [%s];\n", dotID(nodeID(t)), dotID(nodeID(target)), attrs))
+
+				if isLocalType(target, typePkgMap) || seen[target] {
+					continue
+				}
+				seen[target] = true
+				name := typeDisplayName(target, config, typePkgMap)
+				link, err := linkForType(target, config, typePkgMap)
+				if err != nil {
+					return fmt.Errorf("error getting link for type=%s: %w", target.Name, err)
+				}
+				attrs = "style=dashed"
+				if link != "" {
+					attrs += ", label=" + dotID(name+"\n"+link) + ", URL=" + dotID(link)
+				} else {
+					attrs += ", label=" + dotID(name)
+				}
+				external = append(external, fmt.Sprintf("  %s [%s];\n", dotID(nodeID(target)), attrs))
+			}
+		}
+		b.WriteString("  }\n")
+	}
+	if len(external) > 0 {
+		b.WriteString("\n")
+	}
+	for _, v := range external {
+		b.WriteString(v)
+	}
+	b.WriteString("\n")
+	for _, v := range edges {
+		b.WriteString(v)
+	}
+	b.WriteString("}\n")
+
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// dotID returns s as a quoted DOT identifier.
+func dotID(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
@@ -0,0 +1,525 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"math"
+	"regexp"
+	"regexp/syntax"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"k8s.io/gengo/v2/types"
+)
+
+// wellKnownTypeExamples are the example values of types that are serialized
+// differently than their Go type suggests (see wellKnownTypeSchemas).
+var wellKnownTypeExamples = map[string]string{
+	"k8s.io/apimachinery/pkg/apis/meta/v1.Time":                     "2006-01-02T15:04:05Z",
+	"k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime":                "2006-01-02T15:04:05.000000Z",
+	"k8s.io/apimachinery/pkg/apis/meta/v1.Duration":                 "1m0s",
+	"k8s.io/apimachinery/pkg/api/resource.Quantity":                 `"1"`,
+	"k8s.io/apimachinery/pkg/util/intstr.IntOrString":               "0",
+	"k8s.io/apimachinery/pkg/runtime.RawExtension":                  "{}",
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1.JSON": "{}",
+}
+
+// formatExamples are the example values of strings with a
+// +kubebuilder:validation:Format marker.
+var formatExamples = map[string]string{
+	"date":      "2006-01-02",
+	"date-time": "2006-01-02T15:04:05Z",
+	"duration":  "1m0s",
+	"email":     "user@example.com",
+	"hostname":  "example.com",
+	"ipv4":      "192.0.2.1",
+	"ipv6":      "2001:db8::1",
+	"cidr":      "192.0.2.0/24",
+	"uri":       "https://example.com",
+	"uuid":      "00000000-0000-0000-0000-000000000000",
+	"byte":      "",
+	"password":  "",
+}
+
+// exampleYAML returns an example manifest of the top-level API type t. The
+// spec and the required fields are set to their default, the first of their
+// allowed values or a value within their limits; the optional fields are
+// commented out.
+func exampleYAML(t *types.Type, c generatorConfig, typePkgMap map[*types.Type]*apiPackage) string {
+	w := &exampleWriter{config: c, typePkgMap: typePkgMap, ancestors: map[*types.Type]bool{t: true}}
+	w.line(0, nil, "apiVersion: "+apiGroupForType(t, typePkgMap))
+	w.line(0, nil, "kind: "+t.Name.Name)
+	w.line(0, nil, "metadata:")
+	w.line(1, nil, "name: "+strings.ToLower(t.Name.Name)+"-sample")
+	for _, sm := range serializedMembers(t, c) {
+		switch parseJSONTag(sm.Member).name {
+		case "apiVersion", "kind", "metadata", "status":
+			// the status is written by controllers, not users
+			continue
+		case "spec":
+			// the spec is filled in even if it is optional (e.g. with
+			// omitempty), only its optional fields are commented out
+			w.value(0, nil, "spec:", sm.Member)
+			continue
+		}
+		w.field(0, nil, sm.Parent, sm.Member)
+	}
+	return w.b.String()
+}
+
+// preformattedHTML escapes s for a <pre> element, with the indentation of its
+// lines written as character references so that it is kept by postProcess.
+func preformattedHTML(s string) template.HTML {
+	s = template.HTMLEscapeString(s)
+	return template.HTML(regexp.MustCompile(`(?m)^ +`).ReplaceAllStringFunc(s, func(v string) string {
+		return strings.Repeat("&#32;", len(v))
+	}))
+}
+
+// exampleWriter writes the lines of an example manifest.
+type exampleWriter struct {
+	config     generatorConfig
+	typePkgMap map[*types.Type]*apiPackage
+	b          strings.Builder
+
+	// ancestors are the struct types the fields written are nested in.
+	ancestors map[*types.Type]bool
+	// item is set when the next line is the first of an element of a list.
+	item bool
+}
+
+// line writes s at the given indentation level. Lines of fields that are
+// commented out have a "#" at the level of each optional field they are
+// nested in, so that uncommenting a field only takes removing the "# " at its
+// level from its lines, and leaves its optional fields commented out;
+// commented are these levels.
+func (w *exampleWriter) line(level int, commented []int, s string) {
+	var dash string
+	if w.item {
+		dash, level, w.item = "- ", level-1, false
+	}
+	var b strings.Builder
+	for l := 0; l <= level; l++ {
+		if isCommented(commented, l) {
+			b.WriteString("# ")
+		}
+		if l < level {
+			b.WriteString("  ")
+		}
+	}
+	b.WriteString(dash)
+	if dash != "" && isCommented(commented, level+1) {
+		// the first field of a list element is optional
+		b.WriteString("# ")
+	}
+	w.b.WriteString(b.String() + s + "\n")
+}
+
+// isCommented returns whether the lines of a field at the given level are
+// commented out at that level.
+func isCommented(commented []int, level int) bool {
+	for _, l := range commented {
+		if l == level {
+			return true
+		}
+	}
+	return false
+}
+
+// withComment returns commented with level added, leaving commented as is.
+func withComment(commented []int, level int) []int {
+	if isCommented(commented, level) {
+		return commented
+	}
+	return append(commented[:len(commented):len(commented)], level)
+}
+
+// field writes member m of struct type parent, commented out if it is
+// optional. Required fields nested in optional ones are only commented out
+// with them.
+func (w *exampleWriter) field(level int, commented []int, parent *types.Type, m types.Member) {
+	if !isRequiredField(parent, m, w.typePkgMap) {
+		commented = withComment(commented, level)
+	}
+	name := parseJSONTag(m).name
+	if v, ok := memberDefaultValue(m); ok {
+		w.line(level, commented, name+": "+exampleScalar(v))
+		return
+	}
+	w.value(level, commented, name+":", m)
+}
+
+// value writes a value of the type of member m after key, which is the key of
+// the field ("name:"), or empty for the elements of lists. The elements of
+// lists and maps are members without a name or comments.
+func (w *exampleWriter) value(level int, commented []int, key string, m types.Member) {
+	t := m.Type
+	inline := func(v string) {
+		w.line(level, commented, strings.TrimSpace(key+" "+v))
+	}
+
+	elem, suffix := fieldElemType(t)
+	switch {
+	case suffix == "":
+		if v, valid, ok := w.scalar(t, m.CommentLines); ok {
+			// values that break the validation markers are commented out
+			if !valid {
+				commented = withComment(commented, level)
+			}
+			inline(v)
+			return
+		}
+	case strings.HasPrefix(suffix, "[]"):
+		n, min := itemCount(memberConstraints(m), "MinItems", "MaxItems")
+		if w.ancestors[elem] || key == "" {
+			n = 0
+		}
+		if n < min {
+			commented = withComment(commented, level)
+		}
+		if n == 0 {
+			inline("[]")
+			return
+		}
+		w.line(level, commented, key)
+		for i := 0; i < n; i++ {
+			w.item = true
+			w.value(level+1, commented, "", types.Member{Type: collectionElem(t)})
+		}
+		return
+	default:
+		n, min := itemCount(memberConstraints(m), "MinProperties", "MaxProperties")
+		if w.ancestors[elem] || key == "" {
+			n = 0
+		}
+		if n < min {
+			commented = withComment(commented, level)
+		}
+		if n == 0 {
+			inline("{}")
+			return
+		}
+		w.line(level, commented, key)
+		for i := 0; i < n; i++ {
+			k := "key:"
+			if n > 1 {
+				k = fmt.Sprintf("key%d:", i+1)
+			}
+			w.value(level+1, commented, k, types.Member{Type: collectionElem(t)})
+		}
+		return
+	}
+
+	if w.ancestors[elem] || !isLocalType(elem, w.typePkgMap) {
+		inline("{}")
+		return
+	}
+	// a struct without required fields is written empty, with its optional
+	// fields commented out below it
+	members := serializedMembers(elem, w.config)
+	empty := true
+	for _, sm := range members {
+		empty = empty && !isRequiredField(sm.Parent, sm.Member, w.typePkgMap)
+	}
+	if empty {
+		inline("{}")
+	} else if key != "" {
+		w.line(level, commented, key)
+	}
+	if key != "" {
+		level++
+	}
+
+	w.ancestors[elem] = true
+	defer delete(w.ancestors, elem)
+	for _, sm := range members {
+		w.field(level, commented, sm.Parent, sm.Member)
+	}
+}
+
+// itemCount returns the number of elements of the example value of a list or
+// map with the constraints cs: one, or the minimum set with the minName
+// marker, at most the maximum set with the maxName marker. min is the
+// minimum, more than the count if the markers contradict.
+func itemCount(cs []constraint, minName, maxName string) (n, min int) {
+	max := -1
+	for _, c := range cs {
+		v, err := strconv.Atoi(c.Value)
+		if err != nil || v < 0 {
+			continue
+		}
+		switch c.Name {
+		case minName:
+			min = v
+		case maxName:
+			max = v
+		}
+	}
+	n = 1
+	if min > n {
+		n = min
+	}
+	if max >= 0 && n > max {
+		n = max
+	}
+	return n, min
+}
+
+// collectionElem returns the element type of slice or map type t, through
+// pointers and aliases.
+func collectionElem(t *types.Type) *types.Type {
+	for t.Kind == types.Pointer || t.Kind == types.Alias {
+		if t.Kind == types.Pointer {
+			t = t.Elem
+		} else {
+			t = t.Underlying
+		}
+	}
+	return t.Elem
+}
+
+// scalar returns the example value of t if it is serialized as a scalar:
+// the first of its allowed values, or a value within the limits of the
+// validation markers in comments. valid is false if no value meeting all of
+// them was found, e.g. for a Pattern and a MaxLength that contradict.
+func (w *exampleWriter) scalar(t *types.Type, comments []string) (v string, valid, ok bool) {
+	named := tryDereference(t)
+	if v, ok := wellKnownTypeExamples[named.Name.String()]; ok {
+		return v, true, true
+	}
+	if values := enumMarker(comments); values != nil {
+		return exampleScalar(allowedValueJSON(named, allowedValue{Value: values[0]})), true, true
+	}
+	if values := typeAllowedValues(named, w.typePkgMap[named]); len(values) > 0 {
+		return exampleScalar(allowedValueJSON(named, values[0])), true, true
+	}
+	marker := func(name string) (string, bool) {
+		if v, ok := commentTag(comments, "kubebuilder:validation:"+name); ok {
+			return markerString(v), true
+		}
+		if v, ok := commentTag(named.CommentLines, "kubebuilder:validation:"+name); ok {
+			return markerString(v), true
+		}
+		return "", false
+	}
+
+	u := finalUnderlyingTypeOf(named)
+	if u.Kind != types.Builtin {
+		return "", false, false
+	}
+	switch u.Name.Name {
+	case "string":
+		v, valid := exampleString(marker)
+		return exampleScalar(v), valid, true
+	case "bool":
+		return "false", true, true
+	}
+	v, valid = exampleNumber(marker, !strings.HasPrefix(u.Name.Name, "float"))
+	return v, valid, true
+}
+
+// exampleString returns an example string meeting the Format, Pattern,
+// MinLength and MaxLength markers read with marker, and whether it meets
+// them all.
+func exampleString(marker func(name string) (string, bool)) (string, bool) {
+	minLength, maxLength := 0, -1
+	if v, ok := marker("MinLength"); ok {
+		minLength, _ = strconv.Atoi(v)
+	}
+	if v, ok := marker("MaxLength"); ok {
+		if n, err := strconv.Atoi(v); err == nil {
+			maxLength = n
+		}
+	}
+	pattern, hasPattern := marker("Pattern")
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		hasPattern = false
+	}
+
+	var candidates []string
+	if v, ok := marker("Format"); ok {
+		candidates = append(candidates, formatExamples[v])
+	}
+	if hasPattern {
+		if v, ok := patternExample(pattern); ok {
+			candidates = append(candidates, v)
+		}
+	}
+	candidates = append(candidates, strings.Repeat("a", minLength))
+	for _, v := range candidates {
+		n := utf8.RuneCountInString(v)
+		if n >= minLength && (maxLength < 0 || n <= maxLength) && (!hasPattern || re.MatchString(v)) {
+			return v, true
+		}
+	}
+	return candidates[0], false
+}
+
+// patternExample returns the shortest string matching the regular
+// expression pattern, taking the first branch of alternations and the first
+// character of classes, and whether the pattern could be parsed.
+func patternExample(pattern string) (string, bool) {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return "", false
+	}
+	var b strings.Builder
+	var write func(re *syntax.Regexp) bool
+	write = func(re *syntax.Regexp) bool {
+		switch re.Op {
+		case syntax.OpNoMatch:
+			return false
+		case syntax.OpLiteral:
+			b.WriteString(string(re.Rune))
+		case syntax.OpCharClass:
+			r, ok := charClassExample(re.Rune)
+			if !ok {
+				return false
+			}
+			b.WriteRune(r)
+		case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+			b.WriteRune('a')
+		case syntax.OpCapture, syntax.OpPlus, syntax.OpAlternate:
+			return write(re.Sub[0])
+		case syntax.OpRepeat:
+			for i := 0; i < re.Min; i++ {
+				if !write(re.Sub[0]) {
+					return false
+				}
+			}
+		case syntax.OpConcat:
+			for _, sub := range re.Sub {
+				if !write(sub) {
+					return false
+				}
+			}
+		}
+		// anchors, empty matches and optional repetitions write nothing
+		return true
+	}
+	if !write(re) {
+		return "", false
+	}
+	return b.String(), true
+}
+
+// charClassExample returns a readable character of the character class with
+// the given ranges, preferring letters and digits.
+func charClassExample(ranges []rune) (rune, bool) {
+	if len(ranges) == 0 {
+		return 0, false
+	}
+	for _, r := range "a0A-._" {
+		for i := 0; i+1 < len(ranges); i += 2 {
+			if ranges[i] <= r && r <= ranges[i+1] {
+				return r, true
+			}
+		}
+	}
+	for i := 0; i+1 < len(ranges); i += 2 {
+		for r := ranges[i]; r <= ranges[i+1] && r-ranges[i] < 128; r++ {
+			if unicode.IsGraphic(r) && !unicode.IsSpace(r) {
+				return r, true
+			}
+		}
+	}
+	return ranges[0], true
+}
+
+// exampleNumber returns an example number meeting the Minimum, Maximum,
+// ExclusiveMinimum, ExclusiveMaximum and MultipleOf markers read with
+// marker, and whether it meets them all. Numbers are set to their minimum,
+// or their maximum if it is not positive, and 0 otherwise.
+func exampleNumber(marker func(name string) (string, bool), isInt bool) (string, bool) {
+	bound := func(name string) (float64, bool) {
+		v, ok := marker(name)
+		if !ok {
+			return 0, false
+		}
+		f, err := strconv.ParseFloat(v, 64)
+		return f, err == nil
+	}
+	flag := func(name string) bool {
+		v, _ := marker(name)
+		b, _ := strconv.ParseBool(v)
+		return b
+	}
+	min, hasMin := bound("Minimum")
+	max, hasMax := bound("Maximum")
+	exclusiveMin, exclusiveMax := flag("ExclusiveMinimum"), flag("ExclusiveMaximum")
+
+	var v float64
+	down := false
+	switch {
+	case hasMin && isInt && exclusiveMin:
+		v = math.Floor(min) + 1
+	case hasMin && isInt:
+		v = math.Ceil(min)
+	case hasMin && exclusiveMin:
+		// between the bounds if they are close
+		if v = min + 1; hasMax && v >= max {
+			v = min + (max-min)/2
+		}
+	case hasMin:
+		v = min
+	case hasMax && (max < 0 || max == 0 && exclusiveMax):
+		down = true
+		switch {
+		case isInt && exclusiveMax:
+			v = math.Ceil(max) - 1
+		case isInt:
+			v = math.Floor(max)
+		case exclusiveMax:
+			v = max - 1
+		default:
+			v = max
+		}
+	}
+	if k, ok := bound("MultipleOf"); ok && k > 0 && math.Mod(v, k) != 0 {
+		// away from the bound the value was taken from
+		if down {
+			v = math.Floor(v/k) * k
+		} else {
+			v = math.Ceil(v/k) * k
+		}
+	}
+	if v == 0 {
+		v = 0 // not -0
+	}
+
+	valid := (!hasMin || v > min || v == min && !exclusiveMin) &&
+		(!hasMax || v < max || v == max && !exclusiveMax)
+	if isInt {
+		return strconv.FormatFloat(v, 'f', 0, 64), valid
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64), valid
+}
+
+// yamlPlainScalar matches the strings that can be written in YAML without
+// quotes and are not read as another type.
+var yamlPlainScalar = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_./-]*$`)
+
+// exampleScalar returns v as a YAML scalar, or as a JSON object or list,
+// which YAML reads as well.
+func exampleScalar(v interface{}) string {
+	s, ok := v.(string)
+	if !ok {
+		b, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(b)
+	}
+	switch strings.ToLower(s) {
+	case "true", "false", "yes", "no", "on", "off", "y", "n", "null":
+		return strconv.Quote(s)
+	}
+	if yamlPlainScalar.MatchString(s) {
+		return s
+	}
+	return strconv.Quote(s)
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"k8s.io/gengo/v2/types"
+	"k8s.io/klog/v2"
+)
+
+// explainCommand is the subcommand that prints the documentation of an API
+// type or one of its fields to the terminal, like kubectl explain.
+const explainCommand = "explain"
+
+func runExplain(args []string) error {
+	fs := flag.NewFlagSet(explainCommand, flag.ExitOnError)
+	apiDir := fs.String("api-dir", "", "api directory (or import path), point this to pkg/apis")
+	configPath := fs.String("config", "", "path to config file (optional)")
+	recursive := fs.Bool("recursive", false, "print the fields of the nested API types too")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: %s %s -api-dir <dir> [flags] <group/version> <Kind> [field.path]\n\n", os.Args[0], explainCommand)
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if *apiDir == "" || fs.NArg() < 2 || fs.NArg() > 3 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	var config generatorConfig
+	if *configPath != "" {
+		var err error
+		if config, err = loadConfig(*configPath); err != nil {
+			return err
+		}
+	}
+
+	// only errors are of interest on the terminal
+	klog.LogToStderr(false)
+	klog.SetOutput(io.Discard)
+
+	pkgs, err := parseAPIPackages(*apiDir)
+	if err != nil {
+		return err
+	}
+	apiPackages, err := combineAPIPackages(pkgs)
+	if err != nil {
+		return err
+	}
+	return explain(os.Stdout, apiPackages, config, fs.Arg(0), fs.Arg(1), fs.Arg(2), *recursive)
+}
+
+// explain writes the documentation of the API type kind of the API package
+// groupVersion, or of the field at fieldPath (e.g. "spec.nodes.resources")
+// if it is not empty: its type, description and fields.
+func explain(w io.Writer, pkgs []*apiPackage, config generatorConfig, groupVersion, kind, fieldPath string, recursive bool) error {
+	var pkg *apiPackage
+	for _, p := range pkgs {
+		if p.identifier() == groupVersion {
+			pkg = p
+		}
+	}
+	if pkg == nil {
+		return fmt.Errorf("API group/version %q not found", groupVersion)
+	}
+	var root *types.Type
+	for _, t := range visibleTypes(pkg.Types, config) {
+		// like kubectl explain, kinds are matched case-insensitively
+		if strings.EqualFold(t.Name.Name, kind) {
+			root = t
+		}
+	}
+	if root == nil {
+		return fmt.Errorf("type %q not found in %s", kind, groupVersion)
+	}
+
+	typePkgMap := extractTypeToPackageMap(pkgs)
+	t, named, comments := root, root, root.CommentLines
+	var field *types.Member
+	if fieldPath != "" {
+		for _, name := range strings.Split(fieldPath, ".") {
+			name = strings.TrimRight(name, "[]{}")
+			var found bool
+			for _, sm := range serializedMembers(t, config) {
+				if parseJSONTag(sm.Member).name == name {
+					field, found = &sm.Member, true
+					break
+				}
+			}
+			if !found {
+				return fmt.Errorf("field %q does not exist in %s", name, t.Name.Name)
+			}
+			t, _ = fieldElemType(field.Type)
+			named, comments = tryDereference(field.Type), explainComments(*field)
+		}
+	}
+
+	fmt.Fprintf(w, "GROUP:      %s\n", pkg.apiGroup)
+	fmt.Fprintf(w, "KIND:       %s\n", root.Name.Name)
+	fmt.Fprintf(w, "VERSION:    %s\n\n", pkg.apiVersion)
+	if field != nil {
+		fmt.Fprintf(w, "FIELD: %s <%s>\n\n", parseJSONTag(*field).name, typeDisplayName(field.Type, config, typePkgMap))
+	}
+	fmt.Fprintln(w, "DESCRIPTION:")
+	fmt.Fprintln(w, explainText(comments, 4))
+	var values []allowedValue
+	if field != nil {
+		values = memberAllowedValues(*field, typePkgMap)
+	}
+	if values == nil {
+		values = typeAllowedValues(named, typePkgMap[named])
+	}
+	if len(values) > 0 {
+		fmt.Fprintln(w, "\nENUM:")
+		for _, v := range values {
+			fmt.Fprintf(w, "    %s\n", v.Value)
+		}
+	}
+	rules := celRules(comments)
+	if field != nil {
+		// the rules of the type of the field apply to it too
+		rules = append(rules, celRules(named.CommentLines)...)
+	}
+	if len(rules) > 0 {
+		fmt.Fprintln(w, "\nVALIDATION RULES:")
+		for _, r := range rules {
+			if r.Immutable {
+				fmt.Fprintf(w, "    Immutable: %s\n", r.Summary())
+			} else {
+				fmt.Fprintf(w, "    %s\n", r.Summary())
+			}
+			fmt.Fprintf(w, "        %s\n", r.Rule)
+		}
+	}
+
+	// types serialized differently than their Go type suggests have no
+	// fields to explain
+	if _, ok := wellKnownTypeSchemas[t.Name.String()]; ok || t.Kind != types.Struct {
+		return nil
+	}
+	members := serializedMembers(t, config)
+	if len(members) == 0 {
+		return nil
+	}
+
+	fmt.Fprintln(w, "\nFIELDS:")
+	// the fields of TypeMeta are usually hidden from the docs
+	typeMeta := field == nil && isExportedType(root) && hiddenMember(types.Member{Name: "TypeMeta"}, config)
+	if recursive {
+		tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+		if typeMeta {
+			fmt.Fprintf(tw, "  apiVersion\t<string>\n  kind\t<string>\n")
+		}
+		for _, f := range walkFields(t, config, typePkgMap) {
+			name := f.Path[strings.LastIndex(f.Path, ".")+1:]
+			fmt.Fprintf(tw, "  %s%s\t<%s>%s\n", strings.Repeat("  ", f.Depth), name,
+				typeDisplayName(f.Member.Type, config, typePkgMap), explainRequired(f.Parent, f.Member, typePkgMap))
+		}
+		return tw.Flush()
+	}
+	if typeMeta {
+		fmt.Fprintf(w, "  apiVersion\t<string>\n    APIVersion of the object: %s\n\n", pkg.identifier())
+		fmt.Fprintf(w, "  kind\t<string>\n    Kind of the object: %s\n\n", root.Name.Name)
+	}
+	for _, sm := range members {
+		m := sm.Member
+		fmt.Fprintf(w, "  %s\t<%s>%s\n", parseJSONTag(m).name, typeDisplayName(m.Type, config, typePkgMap), explainRequired(sm.Parent, m, typePkgMap))
+		fmt.Fprintf(w, "%s\n\n", explainText(explainComments(m), 4))
+	}
+	return nil
+}
+
+func explainRequired(parent *types.Type, m types.Member, typePkgMap map[*types.Type]*apiPackage) string {
+	if isRequiredField(parent, m, typePkgMap) {
+		return " -required-"
+	}
+	return ""
+}
+
+// explainComments returns the comment lines describing field m: its own, or
+// like kubectl explain, those of the struct type it refers to if it has none.
+func explainComments(m types.Member) []string {
+	if strings.TrimSpace(plainTextComments(m.CommentLines)) != "" {
+		return m.CommentLines
+	}
+	if t, _ := fieldElemType(m.Type); t.Kind == types.Struct {
+		return t.CommentLines
+	}
+	return m.CommentLines
+}
+
+// explainText returns the comment lines as plain text indented with n
+// spaces, or a placeholder if there are none.
+func explainText(s []string, n int) string {
+	doc := strings.TrimSpace(plainTextComments(s))
+	if doc == "" {
+		doc = "<empty>"
+	}
+	return indent(n, doc)
+}
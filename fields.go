@@ -0,0 +1,93 @@
+package main
+
+import (
+	"k8s.io/gengo/v2/types"
+)
+
+// fieldPath is a field of an API type, found by walking its members and the
+// members of the local types they refer to.
+type fieldPath struct {
+	// Path is the path of the field from the root type, e.g.
+	// "spec.template.spec.containers[].image". Elements of slices are
+	// denoted with "[]" and values of maps with "{}".
+	Path string
+	// Depth is the number of fields the field is nested in.
+	Depth  int
+	Member types.Member
+	// Parent is the struct type that declares the member.
+	Parent *types.Type
+	// Recursive is true if the type of the field is one of the types the
+	// field is nested in, so its fields are not walked again.
+	Recursive bool
+}
+
+// walkFields returns the fields of type t, each followed by its own fields if
+// it is of a local struct type. Fields of inline embedded structs are listed
+// as fields of the type they are embedded in, and hidden members are left out.
+func walkFields(t *types.Type, c generatorConfig, typePkgMap map[*types.Type]*apiPackage) []fieldPath {
+	var out []fieldPath
+	var walk func(t *types.Type, prefix string, depth int, ancestors map[*types.Type]bool)
+	walk = func(t *types.Type, prefix string, depth int, ancestors map[*types.Type]bool) {
+		ancestors[t] = true
+		defer delete(ancestors, t)
+
+		for _, sm := range serializedMembers(t, c) {
+			m := sm.Member
+			elem, suffix := fieldElemType(m.Type)
+			f := fieldPath{Path: prefix + parseJSONTag(m).name, Depth: depth, Member: m, Parent: sm.Parent, Recursive: ancestors[elem]}
+			out = append(out, f)
+			if !f.Recursive && elem.Kind == types.Struct && isLocalType(elem, typePkgMap) {
+				walk(elem, f.Path+suffix+".", depth+1, ancestors)
+			}
+		}
+	}
+	walk(t, "", 0, map[*types.Type]bool{})
+	return out
+}
+
+// serializedMember is a member serialized as a field of a struct type, and
+// the type that declares it.
+type serializedMember struct {
+	Member types.Member
+	Parent *types.Type
+}
+
+// serializedMembers returns the members of struct type t that are serialized
+// as fields, with the members of inline embedded structs in place of them.
+// Hidden members are left out.
+func serializedMembers(t *types.Type, c generatorConfig) []serializedMember {
+	var out []serializedMember
+	for _, m := range t.Members {
+		tag := parseJSONTag(m)
+		if tag.skip || hiddenMember(m, c) {
+			continue
+		}
+		if elem, _ := fieldElemType(m.Type); tag.inline && elem.Kind == types.Struct {
+			out = append(out, serializedMembers(elem, c)...)
+			continue
+		}
+		out = append(out, serializedMember{Member: m, Parent: t})
+	}
+	return out
+}
+
+// fieldElemType returns the type whose fields are nested in a field of type
+// t, dereferencing pointers, aliases and the elements of slices and maps, and
+// the path suffix denoting the elements.
+func fieldElemType(t *types.Type) (*types.Type, string) {
+	var suffix string
+	for {
+		switch t.Kind {
+		case types.Pointer:
+			t = t.Elem
+		case types.Slice, types.Array:
+			t, suffix = t.Elem, suffix+"[]"
+		case types.Map:
+			t, suffix = t.Elem, suffix+"{}"
+		case types.Alias:
+			t = t.Underlying
+		default:
+			return t, suffix
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	texttemplate "text/template"
+
+	"k8s.io/gengo/v2/types"
+	"k8s.io/klog/v2"
+)
+
+// hugoConfig configures the output of -output-format=hugo.
+type hugoConfig struct {
+	// PackageAliasTemplates are templates of the URLs to redirect to the page
+	// of an API group/version (e.g. "/docs/{{ .Group }}/{{ .Version }}/").
+	PackageAliasTemplates []string `json:"packageAliasTemplates"`
+
+	// KindAliasTemplates are templates of the URLs to redirect to the page of
+	// a top-level API type when -page-per-kind is set. They can use {{ .Kind }}
+	// in addition to {{ .Group }} and {{ .Version }}.
+	KindAliasTemplates []string `json:"kindAliasTemplates"`
+
+	// SiteDir is the root directory of the Hugo site, where the shortcode
+	// of the field anchors is written to. It defaults to the parent of the
+	// content directory -out-dir is in.
+	SiteDir string `json:"siteDir"`
+}
+
+// hugoAnchorShortcode is the name of the shortcode written with the content
+// that renders the anchors of fields, since raw HTML is left out of the
+// content by default but not out of the output of shortcodes.
+const hugoAnchorShortcode = "gencrdrefdocs-anchor"
+
+// hugoAnchor returns the anchor shortcode for the given id.
+func hugoAnchor(id string) string {
+	return fmt.Sprintf(`{{< %s %q >}}`, hugoAnchorShortcode, id)
+}
+
+// writeHugoAnchorShortcode writes the anchor shortcode to the layouts of the
+// Hugo site with the content in outDir, and returns the path written to.
+func writeHugoAnchorShortcode(c hugoConfig, outDir string) (string, error) {
+	site := c.SiteDir
+	if site == "" {
+		dir, err := filepath.Abs(outDir)
+		if err != nil {
+			return "", err
+		}
+		for filepath.Base(dir) != "content" {
+			parent := filepath.Dir(dir)
+			if parent == dir {
+				return "", fmt.Errorf("cannot find the Hugo site of %s, which is not in a content directory; set hugo.siteDir in the config file", outDir)
+			}
+			dir = parent
+		}
+		site = filepath.Dir(dir)
+	}
+	p := filepath.Join(site, "layouts", "shortcodes", hugoAnchorShortcode+".html")
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return "", fmt.Errorf("failed to create dir %s: %w", filepath.Dir(p), err)
+	}
+	if err := os.WriteFile(p, []byte(`<a id="{{ .Get 0 }}"></a>`+"\n"), 0o644); err != nil {
+		return "", fmt.Errorf("failed to write to %s: %w", p, err)
+	}
+	return p, nil
+}
+
+// hugoRelref turns a link to a page of the generated content into a Hugo
+// relref shortcode, so that Hugo resolves and validates it.
+func hugoRelref(link string) string {
+	if link == "" || strings.HasPrefix(link, "#") {
+		return link
+	}
+	return fmt.Sprintf(`{{< relref %q >}}`, link)
+}
+
+// hugoCollapsible returns the details shortcode with the given summary and
+// Markdown content, since raw HTML is left out of the content by default.
+func hugoCollapsible(summary, content string) string {
+	sep := ""
+	if strings.Contains(content, "\n") {
+		sep = "\n"
+	}
+	return fmt.Sprintf(`{{< details summary=%q >}}`, summary) + sep + content + sep + `{{< /details >}}`
+}
+
+// hugoAliases returns the aliases of the page of the API package p, or of the
+// top-level API type t if it is not nil.
+func hugoAliases(c hugoConfig, p *apiPackage, t *types.Type) []string {
+	if p == nil {
+		return nil
+	}
+	patterns := c.PackageAliasTemplates
+	data := map[string]string{"Group": p.apiGroup, "Version": p.apiVersion}
+	if t != nil {
+		patterns = c.KindAliasTemplates
+		data["Kind"] = t.Name.Name
+	}
+
+	var out []string
+	for _, v := range patterns {
+		tpl, err := texttemplate.New("").Funcs(map[string]interface{}{
+			"lower": strings.ToLower,
+		}).Parse(v)
+		if err != nil {
+			klog.Fatalf("hugo alias template %q failed to parse: %v", v, err)
+		}
+		var b bytes.Buffer
+		if err := tpl.Execute(&b, data); err != nil {
+			klog.Fatalf("hugo alias template %q execution error: %v", v, err)
+		}
+		out = append(out, b.String())
+	}
+	return out
+}
@@ -35,8 +35,16 @@ var (
 	flTemplateDir = flag.String("template-dir", "template", "path to template/ dir")
 	flVersion     = flag.Bool("version", false, "print version and exit")
 
-	flHTTPAddr = flag.String("http-addr", "", "start an HTTP server on specified addr to view the result (e.g. :8080)")
-	flOutFile  = flag.String("out-file", "", "path to output file to save the result")
+	flHTTPAddr     = flag.String("http-addr", "", "start an HTTP server on specified addr to view the result (e.g. :8080)")
+	flOutFile      = flag.String("out-file", "", "path to output file to save the result")
+	flOutDir       = flag.String("out-dir", "", "path to output directory to save the result as multiple files (e.g. one page per API group/version)")
+	flPagePerKind  = flag.Bool("page-per-kind", false, "with -out-dir, also write a separate page for each top-level API type")
+	flSearchIndex  = flag.Bool("search-index", false, "with -output-format=html, also write a client-side search index ("+searchIndexFileName+") next to the output")
+	flEmbedSearch  = flag.Bool("embed-search", false, "with -output-format=html, embed the search index and a search box in single page output")
+	flSiteURL      = flag.String("site-url", "", "with -output-format=html, the URL the output is published at; also write a sitemap ("+sitemapFileName+") and the URLs of all types and fields ("+urlManifestFileName+") next to the output")
+	flExamples     = flag.Bool("examples", false, "add an example manifest of each top-level API type, with its required fields set and its optional ones commented out")
+	flClassDiagram = flag.String("class-diagrams", "", "add Mermaid class diagrams of the types of each API group/version (package) or of each top-level API type (kind)")
+	flOutputFormat = flag.String("output-format", outputFormatHTML, "output format of the result (html, markdown, asciidoc, json, jsonschema, openapi, hugo, mdx, mkdocs, rst, man, llms, dot)")
 
 	// set by go build
 	version string
@@ -46,6 +54,24 @@ const (
 	docCommentForceIncludes = "// +gencrdrefdocs:force"
 )
 
+const (
+	outputFormatHTML       = "html"
+	outputFormatMarkdown   = "markdown"
+	outputFormatAsciiDoc   = "asciidoc"
+	outputFormatJSON       = "json"
+	outputFormatJSONSchema = "jsonschema"
+	outputFormatOpenAPI    = "openapi"
+	outputFormatHugo       = "hugo"
+	outputFormatMDX        = "mdx"
+	outputFormatMkDocs     = "mkdocs"
+	outputFormatRST        = "rst"
+	outputFormatMan        = "man"
+	outputFormatLLMs       = "llms"
+	outputFormatDOT        = "dot"
+)
+
+var outputFormats = []string{outputFormatHTML, outputFormatMarkdown, outputFormatAsciiDoc, outputFormatJSON, outputFormatJSONSchema, outputFormatOpenAPI, outputFormatHugo, outputFormatMDX, outputFormatMkDocs, outputFormatRST, outputFormatMan, outputFormatLLMs, outputFormatDOT}
+
 type generatorConfig struct {
 	// HiddenMemberFields hides fields with specified names on all types.
 	HiddenMemberFields []string `json:"hideMemberFields"`
@@ -67,6 +93,18 @@ type generatorConfig struct {
 
 	// GitCommitDisabled causes the git commit information to be excluded from the output.
 	GitCommitDisabled bool `json:"gitCommitDisabled"`
+
+	// Hugo configures the output of -output-format=hugo.
+	Hugo hugoConfig `json:"hugo"`
+
+	// Docusaurus configures the output of -output-format=mdx.
+	Docusaurus docusaurusConfig `json:"docusaurus"`
+
+	// MkDocs configures the output of -output-format=mkdocs.
+	MkDocs mkdocsConfig `json:"mkdocs"`
+
+	// Man configures the output of -output-format=man.
+	Man manConfig `json:"man"`
 }
 
 type externalPackage struct {
@@ -86,6 +124,9 @@ func (v *apiPackage) identifier() string { return fmt.Sprintf("%s/%s", v.apiGrou
 
 func init() {
 	klog.InitFlags(nil)
+	if len(os.Args) > 1 && os.Args[1] == explainCommand {
+		return // has its own flags, parsed by runExplain
+	}
 	flag.Set("alsologtostderr", "true") // for klog
 	flag.Parse()
 
@@ -103,17 +144,69 @@ func init() {
 	if *flAPIDir == "" {
 		panic("-api-dir not specified")
 	}
-	if *flHTTPAddr == "" && *flOutFile == "" {
-		panic("-out-file or -http-addr must be specified")
+	var outputs int
+	for _, v := range []string{*flHTTPAddr, *flOutFile, *flOutDir} {
+		if v != "" {
+			outputs++
+		}
+	}
+	if outputs == 0 {
+		panic("-out-file, -out-dir or -http-addr must be specified")
+	}
+	if outputs > 1 {
+		panic("only one of -out-file, -out-dir or -http-addr can be specified")
+	}
+	if !containsString(outputFormats, *flOutputFormat) {
+		panic(fmt.Sprintf("unknown -output-format %q", *flOutputFormat))
 	}
-	if *flHTTPAddr != "" && *flOutFile != "" {
-		panic("only -out-file or -http-addr can be specified")
+	if *flOutDir != "" && (*flOutputFormat == outputFormatJSON || *flOutputFormat == outputFormatLLMs || *flOutputFormat == outputFormatDOT) {
+		panic(fmt.Sprintf("-out-dir is not supported with -output-format=%s", *flOutputFormat))
+	}
+	if (*flSearchIndex || *flEmbedSearch) && *flOutputFormat != outputFormatHTML {
+		panic("-search-index and -embed-search are only supported with -output-format=html")
+	}
+	if *flSiteURL != "" && *flOutputFormat != outputFormatHTML {
+		panic("-site-url is only supported with -output-format=html")
+	}
+	if *flExamples {
+		switch *flOutputFormat {
+		case outputFormatJSON, outputFormatJSONSchema, outputFormatOpenAPI, outputFormatLLMs, outputFormatDOT:
+			panic(fmt.Sprintf("-examples is not supported with -output-format=%s", *flOutputFormat))
+		}
+	}
+	switch *flClassDiagram {
+	case "":
+	case classDiagramsPackage, classDiagramsKind:
+		switch *flOutputFormat {
+		case outputFormatJSON, outputFormatJSONSchema, outputFormatOpenAPI, outputFormatMan, outputFormatLLMs, outputFormatDOT:
+			panic(fmt.Sprintf("-class-diagrams is not supported with -output-format=%s", *flOutputFormat))
+		}
+	default:
+		panic(fmt.Sprintf("unknown -class-diagrams %q", *flClassDiagram))
+	}
+	if *flOutDir == "" && (*flOutputFormat == outputFormatHugo || *flOutputFormat == outputFormatMan) {
+		panic(fmt.Sprintf("-output-format=%s requires -out-dir", *flOutputFormat))
 	}
 	if err := resolveTemplateDir(*flTemplateDir); err != nil {
 		panic(err)
 	}
 }
 
+func loadConfig(path string) (generatorConfig, error) {
+	var config generatorConfig
+	f, err := os.Open(path)
+	if err != nil {
+		return config, fmt.Errorf("failed to open config file: %w", err)
+	}
+	defer f.Close()
+	d := json.NewDecoder(f)
+	d.DisallowUnknownFields()
+	if err := d.Decode(&config); err != nil {
+		return config, fmt.Errorf("failed to parse config file: %w", err)
+	}
+	return config, nil
+}
+
 func resolveTemplateDir(dir string) error {
 	path, err := filepath.Abs(dir)
 	if err != nil {
@@ -130,15 +223,17 @@ func resolveTemplateDir(dir string) error {
 func main() {
 	defer klog.Flush()
 
-	f, err := os.Open(*flConfig)
-	if err != nil {
-		klog.Fatalf("failed to open config file: %+v", err)
+	if len(os.Args) > 1 && os.Args[1] == explainCommand {
+		if err := runExplain(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+		return
 	}
-	d := json.NewDecoder(f)
-	d.DisallowUnknownFields()
-	var config generatorConfig
-	if err := d.Decode(&config); err != nil {
-		klog.Fatalf("failed to parse config file: %+v", err)
+
+	config, err := loadConfig(*flConfig)
+	if err != nil {
+		klog.Fatal(err)
 	}
 
 	klog.Infof("parsing go packages in directory %s", *flAPIDir)
@@ -154,17 +249,16 @@ func main() {
 	if err != nil {
 		klog.Fatal(err)
 	}
+	checkAllowedValues(apiPackages, config)
 
 	mkOutput := func() (string, error) {
 		var b bytes.Buffer
-		err := render(&b, apiPackages, config)
+		err := render(&b, apiPackages, config, *flOutputFormat)
 		if err != nil {
 			return "", fmt.Errorf("failed to render the result: %w", err)
 		}
 
-		// remove trailing whitespace from each html line for markdown renderers
-		s := regexp.MustCompile(`(?m)^\s+`).ReplaceAllString(b.String(), "")
-		return s, nil
+		return postProcess(*flOutputFormat, b.String()), nil
 	}
 
 	if *flOutFile != "" {
@@ -180,6 +274,62 @@ func main() {
 			klog.Fatalf("failed to write to out file: %v", err)
 		}
 		klog.Infof("written to %s", *flOutFile)
+
+		if *flSearchIndex {
+			b, err := renderSearchIndex(apiPackages, config, &siteLayout{typePkgMap: extractTypeToPackageMap(apiPackages)})
+			if err != nil {
+				klog.Fatalf("failed to render the search index: %+v", err)
+			}
+			p := filepath.Join(dir, searchIndexFileName)
+			if err := os.WriteFile(p, b, 0o644); err != nil {
+				klog.Fatalf("failed to write to out file: %v", err)
+			}
+			klog.Infof("written to %s", p)
+		}
+		if *flSiteURL != "" {
+			layout := &siteLayout{typePkgMap: extractTypeToPackageMap(apiPackages)}
+			page := filepath.Base(*flOutFile)
+			sitemap, err := renderSitemap(apiPackages, config, layout, page, *flSiteURL)
+			if err != nil {
+				klog.Fatalf("failed to render the sitemap: %+v", err)
+			}
+			manifest, err := renderURLManifest(apiPackages, config, layout, page, *flSiteURL)
+			if err != nil {
+				klog.Fatalf("failed to render the URL manifest: %+v", err)
+			}
+			for name, b := range map[string][]byte{sitemapFileName: sitemap, urlManifestFileName: manifest} {
+				p := filepath.Join(dir, name)
+				if err := os.WriteFile(p, b, 0o644); err != nil {
+					klog.Fatalf("failed to write to out file: %v", err)
+				}
+				klog.Infof("written to %s", p)
+			}
+		}
+	}
+
+	if *flOutDir != "" {
+		files, err := renderFiles(apiPackages, config, *flOutputFormat)
+		if err != nil {
+			klog.Fatalf("failed to render the result: %+v", err)
+		}
+		for _, name := range sortedKeys(files) {
+			p := filepath.Join(*flOutDir, filepath.FromSlash(name))
+			if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+				klog.Fatalf("failed to create dir %s: %v", filepath.Dir(p), err)
+			}
+			if err := os.WriteFile(p, files[name], 0o644); err != nil {
+				klog.Fatalf("failed to write to out file: %v", err)
+			}
+		}
+		klog.Infof("written %d files to %s", len(files), *flOutDir)
+
+		if *flOutputFormat == outputFormatHugo {
+			p, err := writeHugoAnchorShortcode(config.Hugo, *flOutDir)
+			if err != nil {
+				klog.Fatalf("failed to write the anchor shortcode: %v", err)
+			}
+			klog.Infof("written to %s", p)
+		}
 	}
 
 	if *flHTTPAddr != "" {
@@ -253,6 +403,16 @@ func parseAPIPackages(dir string) ([]*types.Package, error) {
 	return pkgs, nil
 }
 
+// sortedKeys returns the keys of m in order.
+func sortedKeys(m map[string][]byte) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
+
 func containsString(sl []string, str string) bool {
 	for _, s := range sl {
 		if str == s {
@@ -374,6 +534,118 @@ func renderComments(s []string, markdown bool) string {
 	return nl2br(doc)
 }
 
+// renderCommentsMarkdown returns the comment lines as Markdown source. Unless
+// markdown is true, characters that Markdown would interpret are escaped so
+// the comment is displayed verbatim.
+func renderCommentsMarkdown(s []string, markdown bool) string {
+	s = filterCommentTags(s)
+	doc := strings.TrimSpace(strings.Join(s, "\n"))
+
+	if markdown {
+		return doc
+	}
+	return markdownEscaper.Replace(doc)
+}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`,
+	"[", `\[`, "]", `\]`, "<", `\<`, ">", `\>`, "#", `\#`)
+
+// markdownTableCell turns s into a single line that can be placed in a
+// GitHub-Flavored Markdown table cell.
+func markdownTableCell(s string) string {
+	var paragraphs []string
+	for _, p := range regexp.MustCompile(`\n\s*\n`).Split(strings.TrimSpace(s), -1) {
+		p = strings.Join(strings.Fields(p), " ")
+		if p != "" {
+			paragraphs = append(paragraphs, p)
+		}
+	}
+	s = strings.Join(paragraphs, "<br/><br/>")
+	return strings.Replace(s, "|", `\|`, -1)
+}
+
+// plainTextComments returns the comment lines as plain text, with the Markdown
+// markup for emphasis, code and links removed.
+func plainTextComments(s []string) string {
+	doc := renderCommentsMarkdown(s, true)
+	doc = mdAutoLink.ReplaceAllString(doc, "$1")
+	doc = mdLink.ReplaceAllString(doc, "$1 ($2)")
+	return strings.NewReplacer("**", "", "__", "", "`", "").Replace(doc)
+}
+
+// markdownHeading returns a Markdown heading with the given anchor.
+func markdownHeading(level int, text, id string) string {
+	return fmt.Sprintf("<a id=\"%s\"></a>\n\n%s %s", id, strings.Repeat("#", level), text)
+}
+
+// markdownAnchor returns an empty HTML anchor with the given id, to link to
+// places like table rows that cannot have an id otherwise.
+func markdownAnchor(id string) string { return fmt.Sprintf(`<a id="%s"></a>`, id) }
+
+// markdownTextEscaper escapes the characters of plain text that Markdown would
+// parse as markup. Pipes are left to markdownTableCell.
+var markdownTextEscaper = strings.NewReplacer(`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`, "<", `\<`, ">", `\>`, "#", `\#`)
+
+// markdownText escapes the plain text s for Markdown.
+func markdownText(s string) string { return markdownTextEscaper.Replace(s) }
+
+// markdownCollapsible returns a <details> element with the given summary and
+// Markdown content. Content of more than one line is separated from the tags
+// with blank lines so that it is parsed as Markdown.
+func markdownCollapsible(summary, content string) string {
+	if !strings.Contains(content, "\n") {
+		return "<details><summary>" + summary + "</summary>" + content + "</details>"
+	}
+	return "<details><summary>" + summary + "</summary>\n\n" + content + "\n\n</details>"
+}
+
+// markdownCodeSpan formats s as a code span, delimited with enough backticks
+// for the ones in s.
+func markdownCodeSpan(s string) string {
+	delim := "`"
+	for strings.Contains(s, delim) {
+		delim += "`"
+	}
+	if strings.HasPrefix(s, "`") || strings.HasSuffix(s, "`") {
+		s = " " + s + " "
+	}
+	return delim + s + delim
+}
+
+// markdownHeadingAttr returns a Markdown heading with the given anchor as a
+// heading attribute, for renderers that do not accept raw HTML in content
+// (e.g. Hugo, MDX).
+func markdownHeadingAttr(level int, text, id string) string {
+	return fmt.Sprintf("%s %s {#%s}", strings.Repeat("#", level), text, id)
+}
+
+// commentSummary returns the first paragraph of the comment lines as a single
+// line of plain text.
+func commentSummary(s []string) string {
+	doc := plainTextComments(s)
+	doc = regexp.MustCompile(`\n\s*\n`).Split(doc, 2)[0]
+	return strings.Join(strings.Fields(doc), " ")
+}
+
+// indent indents the non-empty lines of s with n spaces, e.g. to nest them in
+// a list item.
+func indent(n int, s string) string {
+	lines := strings.Split(s, "\n")
+	for i, v := range lines {
+		if strings.TrimSpace(v) != "" {
+			lines[i] = strings.Repeat(" ", n) + v
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// yamlString quotes s as a YAML string.
+func yamlString(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
+
 func safe(s string) template.HTML { return template.HTML(s) }
 
 func nl2br(s string) string {
@@ -412,6 +684,22 @@ func anchorIDForLocalType(t *types.Type, typePkgMap map[*types.Type]*apiPackage)
 	return fmt.Sprintf("%s.%s", apiGroupForType(t, typePkgMap), t.Name.Name)
 }
 
+// fieldAnchorID returns the #anchor string for the field at path (e.g.
+// "spec.nodes[].name") of the local type t. Path elements are separated by
+// dots alone, so that the anchors read like the paths used by kubectl.
+func fieldAnchorID(t *types.Type, path string, typePkgMap map[*types.Type]*apiPackage) string {
+	return anchorIDForLocalType(t, typePkgMap) + "." + strings.NewReplacer("[]", "", "{}", "").Replace(path)
+}
+
+var invalidAnchorIDChars = regexp.MustCompile(`[^\w.-]+`)
+
+// sanitizeAnchorID turns s (e.g. an anchor generated by anchorIDForLocalType)
+// into an ID for formats that do not accept characters like "/" in anchors,
+// such as AsciiDoc or Markdown heading attributes.
+func sanitizeAnchorID(s string) string {
+	return invalidAnchorIDChars.ReplaceAllString(s, "-")
+}
+
 // linkForType returns an anchor to the type if it can be generated. returns
 // empty string if it is not a local type or unrecognized external type.
 func linkForType(t *types.Type, c generatorConfig, typePkgMap map[*types.Type]*apiPackage) (string, error) {
@@ -612,12 +900,6 @@ func filterCommentTags(comments []string) []string {
 	return out
 }
 
-func isOptionalMember(m types.Member) bool {
-	tags := gengo.ExtractCommentTags("+", m.CommentLines)
-	_, ok := tags["optional"]
-	return ok
-}
-
 func apiVersionForPackage(pkg *types.Package) (string, string, error) {
 	group := groupName(pkg)
 	version := pkg.Name // assumes basename (i.e. "v1" in "core/v1") is apiVersion
@@ -669,11 +951,122 @@ func constantsOfType(t *types.Type, pkg *apiPackage) []*types.Type {
 	return sortTypes(constants)
 }
 
-func render(w io.Writer, pkgs []*apiPackage, config generatorConfig) error {
+func render(w io.Writer, pkgs []*apiPackage, config generatorConfig, format string) error {
+	switch format {
+	case outputFormatJSON:
+		return renderJSON(w, pkgs, config)
+	case outputFormatJSONSchema:
+		return renderJSONSchemaBundle(w, pkgs, config)
+	case outputFormatOpenAPI:
+		return renderOpenAPI(w, pkgs, config)
+	case outputFormatDOT:
+		return renderDOT(w, pkgs, config)
+	}
+
+	layout := &siteLayout{typePkgMap: extractTypeToPackageMap(pkgs)}
+	t, err := parseTemplates(format, templateFuncs(pkgs, config, format, layout, ""))
+	if err != nil {
+		return err
+	}
+
+	data := map[string]interface{}{
+		"packages":  pkgs,
+		"config":    config,
+		"gitCommit": gitCommit(config),
+	}
+	if format == outputFormatHTML && *flEmbedSearch {
+		if data["searchIndex"], err = embeddedSearchIndex(pkgs, config, layout); err != nil {
+			return err
+		}
+	}
+	if err := t.ExecuteTemplate(w, "packages", data); err != nil {
+		return fmt.Errorf("template execution error: %w", err)
+	}
+
+	return nil
+}
+
+// nestedMembers is a struct type whose members are rendered in the table of
+// the type of one of its fields, as well as in its own table. Only the rows of
+// its own table get anchors.
+type nestedMembers struct{ *types.Type }
+
+// templateExecutor is implemented by both html/template and text/template
+// templates.
+type templateExecutor interface {
+	ExecuteTemplate(w io.Writer, name string, data interface{}) error
+}
+
+// parseTemplates parses the templates of the given output format.
+func parseTemplates(format string, funcs map[string]interface{}) (templateExecutor, error) {
+	var t templateExecutor
+	var err error
+	switch format {
+	case outputFormatMarkdown, outputFormatAsciiDoc, outputFormatRST, outputFormatMan, outputFormatLLMs:
+		t, err = texttemplate.New("").Funcs(funcs).ParseGlob(filepath.Join(*flTemplateDir, format, "*.tpl"))
+	case outputFormatHugo, outputFormatMDX, outputFormatMkDocs:
+		// the markdown templates, with some of them overridden
+		var tpl *texttemplate.Template
+		tpl, err = texttemplate.New("").Funcs(funcs).ParseGlob(filepath.Join(*flTemplateDir, outputFormatMarkdown, "*.tpl"))
+		if err == nil {
+			t, err = tpl.ParseGlob(filepath.Join(*flTemplateDir, format, "*.tpl"))
+		}
+	default:
+		t, err = template.New("").Funcs(funcs).ParseGlob(filepath.Join(*flTemplateDir, "*.tpl"))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse error: %w", err)
+	}
+	return t, nil
+}
+
+// templateFuncs returns the functions available to the templates of the
+// given output format, when rendering the page at path page of the layout.
+func templateFuncs(pkgs []*apiPackage, config generatorConfig, format string, layout *siteLayout, page string) map[string]interface{} {
 	references := findTypeReferences(pkgs)
-	typePkgMap := extractTypeToPackageMap(pkgs)
+	typePkgMap := layout.typePkgMap
+
+	packageAnchorID := func(p *apiPackage) string {
+		// TODO(ahmetb): currently this is the same as packageDisplayName
+		// func, and it's fine since it returns valid DOM id strings like
+		// 'serving.knative.dev/v1alpha1' which is valid per HTML5, except
+		// spaces, so just trim those.
+		return strings.Replace(p.identifier(), " ", "", -1)
+	}
+	anchorIDForType := func(t *types.Type) string { return anchorIDForLocalType(t, typePkgMap) }
+	fieldAnchorIDForType := func(t *types.Type, path string) string { return fieldAnchorID(t, path, typePkgMap) }
+	var sanitizeAnchors bool
+	switch format {
+	case outputFormatAsciiDoc, outputFormatHugo, outputFormatMDX, outputFormatMkDocs, outputFormatRST:
+		sanitizeAnchors = true
+		packageAnchorID = func(p *apiPackage) string { return sanitizeAnchorID(p.identifier()) }
+		anchorIDForType = func(t *types.Type) string { return sanitizeAnchorID(anchorIDForLocalType(t, typePkgMap)) }
+		fieldAnchorIDForType = func(t *types.Type, path string) string { return sanitizeAnchorID(fieldAnchorID(t, path, typePkgMap)) }
+	}
+	// links are relative to the page they are on, except in AsciiDoc where
+	// cross-references to other pages are resolved from the root of the
+	// site (e.g. the pages directory of an Antora module).
+	linkFrom := page
+	if format == outputFormatAsciiDoc {
+		linkFrom = ""
+	}
+	linkForType := func(t *types.Type) string {
+		v, err := layout.linkForType(t, config, linkFrom)
+		if err != nil {
+			klog.Fatal(fmt.Errorf("error getting link for type=%s: %w", t.Name, err))
+			return ""
+		}
+		if sanitizeAnchors && isLocalType(t, typePkgMap) {
+			page, anchor, _ := strings.Cut(v, "#")
+			v = page + "#" + sanitizeAnchorID(anchor)
+		}
+		return v
+	}
+	packageLink := func(p *apiPackage) string {
+		return relativeLink(linkFrom, layout.packagePage(p), packageAnchorID(p))
+	}
 
-	t, err := template.New("").Funcs(map[string]interface{}{
+	funcs := map[string]interface{}{
 		"isExportedType":     isExportedType,
 		"fieldName":          fieldName,
 		"fieldEmbedded":      fieldEmbedded,
@@ -683,48 +1076,235 @@ func render(w io.Writer, pkgs []*apiPackage, config generatorConfig) error {
 		"renderComments":     func(s []string) string { return renderComments(s, !config.MarkdownDisabled) },
 		"packageDisplayName": func(p *apiPackage) string { return p.identifier() },
 		"apiGroup":           func(t *types.Type) string { return apiGroupForType(t, typePkgMap) },
-		"packageAnchorID": func(p *apiPackage) string {
-			// TODO(ahmetb): currently this is the same as packageDisplayName
-			// func, and it's fine since it returns valid DOM id strings like
-			// 'serving.knative.dev/v1alpha1' which is valid per HTML5, except
-			// spaces, so just trim those.
-			return strings.Replace(p.identifier(), " ", "", -1)
+		"packageAnchorID":    packageAnchorID,
+		"packageLink":        packageLink,
+		"linkForType":        linkForType,
+		"anchorIDForType":    anchorIDForType,
+		"fieldAnchorID":      fieldAnchorIDForType,
+		"typesOnPage":        func(ts []*types.Type) []*types.Type { return layout.typesOnPage(ts, page) },
+		"safe":               safe,
+		"sortedTypes":        sortTypes,
+		"typeReferences":     func(t *types.Type) []*types.Type { return typeReferences(t, config, references) },
+		"hiddenMember":       func(m types.Member) bool { return hiddenMember(m, config) },
+		"isLocalType":        isLocalType,
+		// the type declaring the field is optional, without it the
+		// marker of its package is not taken into account
+		"isOptionalMember": func(m types.Member, parent ...*types.Type) bool {
+			return isOptionalField(firstType(parent), m, typePkgMap)
 		},
-		"linkForType": func(t *types.Type) string {
-			v, err := linkForType(t, config, typePkgMap)
-			if err != nil {
-				klog.Fatal(fmt.Errorf("error getting link for type=%s: %w", t.Name, err))
+		"isRequiredMember": func(m types.Member, parent ...*types.Type) bool {
+			return isRequiredField(firstType(parent), m, typePkgMap)
+		},
+		"isOptionalField": func(parent *types.Type, m types.Member) bool {
+			return isOptionalField(parent, m, typePkgMap)
+		},
+		"isRequiredField": func(parent *types.Type, m types.Member) bool {
+			return isRequiredField(parent, m, typePkgMap)
+		},
+		"constraints":     memberConstraints,
+		"memberDefault":   memberDefault,
+		"celRules":        celRules,
+		"text":            func(s string) string { return s },
+		"constantsOfType": func(t *types.Type) []*types.Type { return constantsOfType(t, typePkgMap[t]) },
+		"allowedValues":   func(t *types.Type) []allowedValue { return typeAllowedValues(t, typePkgMap[t]) },
+		"memberAllowedValues": func(m types.Member) []allowedValue {
+			return memberAllowedValues(m, typePkgMap)
+		},
+		"kindTypes":      func(p *apiPackage) []*types.Type { return kindTypes([]*apiPackage{p}, config) },
+		"fieldPaths":     func(t *types.Type) []fieldPath { return walkFields(t, config, typePkgMap) },
+		"commentSummary": commentSummary,
+		"yamlString":     yamlString,
+		"exampleYAML": func(t *types.Type) string {
+			if !*flExamples {
 				return ""
 			}
-			return v
+			return exampleYAML(t, config, typePkgMap)
 		},
-		"anchorIDForType":  func(t *types.Type) string { return anchorIDForLocalType(t, typePkgMap) },
-		"safe":             safe,
-		"sortedTypes":      sortTypes,
-		"typeReferences":   func(t *types.Type) []*types.Type { return typeReferences(t, config, references) },
-		"hiddenMember":     func(m types.Member) bool { return hiddenMember(m, config) },
-		"isLocalType":      isLocalType,
-		"isOptionalMember": isOptionalMember,
-		"constantsOfType":  func(t *types.Type) []*types.Type { return constantsOfType(t, typePkgMap[t]) },
-	}).ParseGlob(filepath.Join(*flTemplateDir, "*.tpl"))
-	if err != nil {
-		return fmt.Errorf("parse error: %w", err)
+		"indent": indent,
+		"packageClassDiagram": func(p *apiPackage) string {
+			if *flClassDiagram != classDiagramsPackage {
+				return ""
+			}
+			return mermaidClassDiagram(packageDiagramTypes(p, config), config, typePkgMap)
+		},
+		"kindClassDiagram": func(t *types.Type) string {
+			if *flClassDiagram != classDiagramsKind {
+				return ""
+			}
+			return mermaidClassDiagram(kindDiagramTypes(t, config, typePkgMap), config, typePkgMap)
+		},
+	}
+
+	switch format {
+	case outputFormatHTML:
+		funcs["nested"] = func(t *types.Type) nestedMembers { return nestedMembers{t} }
+		funcs["isNested"] = func(v interface{}) bool {
+			_, ok := v.(nestedMembers)
+			return ok
+		}
+		funcs["exampleYAML"] = func(t *types.Type) template.HTML {
+			if !*flExamples {
+				return ""
+			}
+			return preformattedHTML(exampleYAML(t, config, typePkgMap))
+		}
+	case outputFormatMarkdown:
+		funcs["renderComments"] = func(s []string) string { return renderCommentsMarkdown(s, !config.MarkdownDisabled) }
+		funcs["tableCell"] = markdownTableCell
+		funcs["heading"] = markdownHeading
+		funcs["anchor"] = markdownAnchor
+		funcs["literal"] = markdownCodeSpan
+		funcs["text"] = markdownText
+		funcs["collapsible"] = markdownCollapsible
+	case outputFormatHugo:
+		funcs["renderComments"] = func(s []string) string { return renderCommentsMarkdown(s, !config.MarkdownDisabled) }
+		funcs["tableCell"] = markdownTableCell
+		funcs["heading"] = markdownHeadingAttr
+		funcs["anchor"] = hugoAnchor
+		funcs["literal"] = markdownCodeSpan
+		funcs["text"] = markdownText
+		funcs["collapsible"] = hugoCollapsible
+		funcs["linkForType"] = func(t *types.Type) string {
+			if !isLocalType(t, typePkgMap) {
+				return linkForType(t)
+			}
+			return hugoRelref(linkForType(t))
+		}
+		funcs["packageLink"] = func(p *apiPackage) string { return hugoRelref(packageLink(p)) }
+		funcs["hugoAliases"] = func(p *apiPackage, t *types.Type) []string { return hugoAliases(config.Hugo, p, t) }
+	case outputFormatMDX:
+		funcs["renderComments"] = func(s []string) string { return renderCommentsMDX(s, !config.MarkdownDisabled) }
+		funcs["tableCell"] = markdownTableCell
+		funcs["heading"] = markdownHeadingAttr
+		funcs["anchor"] = markdownAnchor
+		funcs["literal"] = markdownCodeSpan
+		funcs["text"] = mdxText
+		funcs["collapsible"] = markdownCollapsible
+	case outputFormatMkDocs:
+		funcs["renderComments"] = func(s []string) string {
+			rest, _ := splitDeprecation(s)
+			return renderCommentsMarkdown(rest, !config.MarkdownDisabled)
+		}
+		funcs["tableCell"] = markdownTableCell
+		funcs["deprecationNotice"] = func(s []string) string { return mkdocsDeprecationNotice(s, !config.MarkdownDisabled) }
+		funcs["anchor"] = markdownAnchor
+		funcs["literal"] = markdownCodeSpan
+		funcs["text"] = markdownText
+		funcs["collapsible"] = markdownCollapsible
+		funcs["heading"] = markdownHeadingAttr
+	case outputFormatAsciiDoc:
+		funcs["renderComments"] = func(s []string) string { return renderCommentsAsciiDoc(s, !config.MarkdownDisabled) }
+		funcs["tableCell"] = asciidocTableCell
+		funcs["literal"] = asciidocLiteral
+		funcs["text"] = asciidocText
+		funcs["typeLink"] = func(t *types.Type) string {
+			return asciidocTypeLink(t, linkForType(t), config, typePkgMap)
+		}
+	case outputFormatRST:
+		funcs["renderComments"] = func(s []string) string { return renderCommentsRST(s, !config.MarkdownDisabled) }
+		funcs["tableCell"] = rstListTableCell
+		funcs["literal"] = rstLiteral
+		funcs["text"] = rstText
+		funcs["heading"] = rstHeading
+		funcs["typeLink"] = func(t *types.Type) string {
+			return rstTypeLink(t, linkForType(t), config, typePkgMap)
+		}
+		// documents are referred to by their path without extension, e.g.
+		// in a toctree.
+		document := func(to string) string {
+			return strings.TrimSuffix(strings.TrimSuffix(relativeLink(page, to, ""), "#"), layout.ext)
+		}
+		funcs["packageDocument"] = func(p *apiPackage) string { return document(layout.packagePage(p)) }
+		funcs["kindDocuments"] = func(p *apiPackage) []string {
+			var out []string
+			for _, t := range kindTypes([]*apiPackage{p}, config) {
+				if v := document(layout.typePage(t)); v != "" {
+					out = append(out, v)
+				}
+			}
+			return out
+		}
+	case outputFormatMan:
+		funcs["manEscape"] = manEscape
+		funcs["manText"] = manText
+		funcs["manPageName"] = func(t *types.Type) string {
+			v, err := manPageName(config.Man, t, typePkgMap)
+			if err != nil {
+				klog.Fatal(err)
+			}
+			return v
+		}
+		funcs["upper"] = strings.ToUpper
+	case outputFormatLLMs:
+		funcs["enumValues"] = func(t *types.Type) []string {
+			t = tryDereference(t)
+			var out []string
+			for _, v := range typeAllowedValues(t, typePkgMap[t]) {
+				out = append(out, v.Value)
+			}
+			return out
+		}
+		funcs["memberEnumValues"] = func(m types.Member) []string {
+			values := memberAllowedValues(m, typePkgMap)
+			if values == nil {
+				t := tryDereference(m.Type)
+				values = typeAllowedValues(t, typePkgMap[t])
+			}
+			var out []string
+			for _, v := range values {
+				out = append(out, v.Value)
+			}
+			return out
+		}
+		funcs["join"] = strings.Join
 	}
+	return funcs
+}
 
-	var gitCommit []byte
-	if !config.GitCommitDisabled {
-		gitCommit, _ = exec.Command("git", "rev-parse", "--short", "HEAD").Output()
+// gitCommit returns the commit of the working directory to be shown in the
+// output, unless disabled by config.
+func gitCommit(config generatorConfig) string {
+	if config.GitCommitDisabled {
+		return ""
 	}
+	out, _ := exec.Command("git", "rev-parse", "--short", "HEAD").Output()
+	return strings.TrimSpace(string(out))
+}
 
-	if err := t.ExecuteTemplate(w, "packages", map[string]interface{}{
-		"packages":  pkgs,
-		"config":    config,
-		"gitCommit": strings.TrimSpace(string(gitCommit)),
-	}); err != nil {
-		return fmt.Errorf("template execution error: %w", err)
+// postProcess cleans up the rendered output of the given format.
+func postProcess(format, s string) string {
+	switch format {
+	case outputFormatHTML:
+		// remove trailing whitespace from each html line for markdown renderers
+		return regexp.MustCompile(`(?m)^\s+`).ReplaceAllString(s, "")
+	case outputFormatMarkdown, outputFormatAsciiDoc, outputFormatHugo, outputFormatMDX, outputFormatMkDocs, outputFormatLLMs:
+		// collapse the blank lines left behind by template actions
+		return regexp.MustCompile(`\n{3,}`).ReplaceAllString(s, "\n\n")
+	case outputFormatMan:
+		// blank lines are printed as vertical space by roff
+		return regexp.MustCompile(`(?m)^[ \t]*\n`).ReplaceAllString(s, "")
+	case outputFormatRST:
+		// also remove the indentation of empty list-table cells
+		s = regexp.MustCompile(`(?m)[ \t]+$`).ReplaceAllString(s, "")
+		return regexp.MustCompile(`\n{3,}`).ReplaceAllString(s, "\n\n")
 	}
+	return s
+}
 
-	return nil
+// renderFiles renders the result as multiple files, keyed by their
+// slash-separated path relative to the output directory.
+func renderFiles(pkgs []*apiPackage, config generatorConfig, format string) (map[string][]byte, error) {
+	switch format {
+	case outputFormatJSONSchema:
+		return renderJSONSchemaFiles(pkgs, config)
+	case outputFormatOpenAPI:
+		return renderOpenAPIFiles(pkgs, config)
+	case outputFormatHTML, outputFormatMarkdown, outputFormatAsciiDoc, outputFormatHugo, outputFormatMDX, outputFormatMkDocs, outputFormatRST:
+		return renderSite(pkgs, config, format, *flPagePerKind)
+	case outputFormatMan:
+		return renderManPages(pkgs, config)
+	}
+	return nil, fmt.Errorf("output format %q cannot be written to a directory", format)
 }
 
 func getBuildInfo() (string, string, bool) {
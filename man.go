@@ -0,0 +1,134 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	texttemplate "text/template"
+
+	"k8s.io/gengo/v2/types"
+)
+
+// defaultManPageNameTemplate is the default template of the names of the man
+// pages, modeled after the fully qualified resource names used by kubectl.
+const defaultManPageNameTemplate = "{{ lower .Kind }}.{{ .Version }}.{{ .Group }}"
+
+// manConfig configures the output of -output-format=man.
+type manConfig struct {
+	// PageNameTemplate is the template of the names of the man pages of
+	// top-level API types (e.g. "myoperator-{{ .Kind }}"). It can use
+	// {{ .Group }}, {{ .Version }} and {{ .Kind }}.
+	PageNameTemplate string `json:"pageNameTemplate"`
+}
+
+// manPageName returns the name of the man page of the top-level API type t.
+func manPageName(c manConfig, t *types.Type, typePkgMap map[*types.Type]*apiPackage) (string, error) {
+	pattern := c.PageNameTemplate
+	if pattern == "" {
+		pattern = defaultManPageNameTemplate
+	}
+	tpl, err := texttemplate.New("").Funcs(map[string]interface{}{
+		"lower": strings.ToLower,
+	}).Parse(pattern)
+	if err != nil {
+		return "", fmt.Errorf("man page name template failed to parse: %w", err)
+	}
+
+	pkg := typePkgMap[t]
+	var b bytes.Buffer
+	if err := tpl.Execute(&b, map[string]string{
+		"Group":   pkg.apiGroup,
+		"Version": pkg.apiVersion,
+		"Kind":    t.Name.Name,
+	}); err != nil {
+		return "", fmt.Errorf("man page name template execution error: %w", err)
+	}
+	// the name is the file name of the page in man5, so it cannot have path
+	// separators that would write it elsewhere
+	name := b.String()
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return "", fmt.Errorf("man page name %q of %s is not a valid file name", name, t.Name.Name)
+	}
+	return name, nil
+}
+
+// manPageFileName returns the path of the man page with the given name,
+// relative to the output directory (e.g. a directory in MANPATH).
+func manPageFileName(name string) string { return path.Join("man5", name+".5") }
+
+// manEscape escapes s so that it is printed verbatim by roff.
+func manEscape(s string) string {
+	s = strings.Replace(s, `\`, `\e`, -1)
+	return manControlLine.ReplaceAllString(s, `\&$1`)
+}
+
+// manControlLine matches the lines roff would read as requests.
+var manControlLine = regexp.MustCompile(`(?m)^([.'])`)
+
+// manText returns the comment lines as roff paragraphs, keeping the lines of
+// code blocks and list items.
+func manText(s []string) string {
+	var paragraphs []string
+	for _, p := range regexp.MustCompile(`\n\s*\n`).Split(plainTextComments(s), -1) {
+		lines := strings.Split(p, "\n")
+		code := true
+		for _, v := range lines {
+			code = code && mdIndentedCode.MatchString(v)
+		}
+
+		var out []string
+		if code {
+			out = append(out, ".nf")
+		}
+		for _, v := range lines {
+			if code {
+				out = append(out, manEscape(strings.TrimPrefix(strings.TrimPrefix(v, "\t"), "    ")))
+			} else if m := mdBulletItem.FindStringSubmatch(v); m != nil {
+				out = append(out, ".br", `\(bu `+manEscape(m[2]))
+			} else if v = strings.TrimSpace(v); v != "" {
+				out = append(out, manEscape(v))
+			}
+		}
+		if code {
+			out = append(out, ".fi")
+		}
+		if len(out) > 0 {
+			paragraphs = append(paragraphs, strings.Join(out, "\n"))
+		}
+	}
+	return strings.Join(paragraphs, "\n.sp\n")
+}
+
+// renderManPages renders a section 5 man page for each top-level API type.
+func renderManPages(pkgs []*apiPackage, config generatorConfig) (map[string][]byte, error) {
+	layout := &siteLayout{typePkgMap: extractTypeToPackageMap(pkgs)}
+	t, err := parseTemplates(outputFormatMan, templateFuncs(pkgs, config, outputFormatMan, layout, ""))
+	if err != nil {
+		return nil, err
+	}
+	commit := gitCommit(config)
+
+	out := make(map[string][]byte)
+	for _, pkg := range pkgs {
+		for _, kind := range kindTypes([]*apiPackage{pkg}, config) {
+			name, err := manPageName(config.Man, kind, layout.typePkgMap)
+			if err != nil {
+				return nil, err
+			}
+			var b bytes.Buffer
+			if err := t.ExecuteTemplate(&b, "manPage", map[string]interface{}{
+				"name":      name,
+				"package":   pkg,
+				"type":      kind,
+				"config":    config,
+				"gitCommit": commit,
+			}); err != nil {
+				return nil, fmt.Errorf("template execution error for man page %s: %w", name, err)
+			}
+			out[manPageFileName(name)] = []byte(postProcess(outputFormatMan, b.String()))
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,424 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"k8s.io/gengo/v2"
+	"k8s.io/gengo/v2/types"
+	"k8s.io/klog/v2"
+)
+
+// commentTag returns the value of the last "+name=value" tag in the comment
+// lines, and whether there is one.
+func commentTag(lines []string, name string) (string, bool) {
+	v := gengo.ExtractCommentTags("+", lines)[name]
+	if len(v) == 0 {
+		return "", false
+	}
+	return v[len(v)-1], true
+}
+
+// memberDefault returns the default value of the field set with the
+// +kubebuilder:default or +default markers as it is written in the marker,
+// or "" if there is none.
+func memberDefault(m types.Member) string {
+	// "+kubebuilder:default:=" is the syntax of newer controller-gen
+	// releases, which is parsed as the tag "kubebuilder:default:".
+	for _, name := range []string{"kubebuilder:default", "kubebuilder:default:", "default"} {
+		if v, ok := commentTag(m.CommentLines, name); ok {
+			return v
+		}
+	}
+	return ""
+}
+
+// memberDefaultValue returns the default value of the field decoded from
+// JSON, or as a string if the marker has a bare string (e.g.
+// "+kubebuilder:default=Fast"), and whether there is one. Defaults referring
+// to a Go constant (e.g. "+default=ref(ModeFast)") have no value here.
+func memberDefaultValue(m types.Member) (interface{}, bool) {
+	v := memberDefault(m)
+	if v == "" || strings.HasPrefix(v, "ref(") {
+		return nil, false
+	}
+	var out interface{}
+	if err := json.Unmarshal([]byte(v), &out); err != nil {
+		return v, true
+	}
+	return out, true
+}
+
+// requirementMarker returns whether the markers in the comment lines of a
+// field make it required or optional, and whether they have such a marker.
+// The markers are checked in the order controller-gen checks them.
+func requirementMarker(lines []string) (required bool, ok bool) {
+	for _, marker := range []struct {
+		name     string
+		required bool
+	}{
+		{"kubebuilder:validation:Optional", false},
+		{"kubebuilder:validation:Required", true},
+		{"optional", false},
+		{"required", true},
+	} {
+		if _, ok := commentTag(lines, marker.name); ok {
+			return marker.required, true
+		}
+	}
+	return false, false
+}
+
+// packageRequirement returns whether the +kubebuilder:validation:Required or
+// Optional marker of the Go package declaring struct type parent makes its
+// fields required or optional by default, and whether it has such a marker.
+// parent is nil if it is not known.
+func packageRequirement(parent *types.Type, typePkgMap map[*types.Type]*apiPackage) (required bool, ok bool) {
+	pkg := typePkgMap[parent]
+	if pkg == nil {
+		return false, false
+	}
+	for _, p := range pkg.GoPackages {
+		if p.Path == parent.Name.Package {
+			return requirementMarker(p.Comments)
+		}
+	}
+	return false, false
+}
+
+// isRequiredField returns true if field m of struct type parent must be set
+// in the serialized object. Like controller-gen, the markers of the field
+// decide first. Otherwise the fields of a package marked
+// +kubebuilder:validation:Optional are optional, and the fields of other
+// packages are required unless they are left out when empty (omitempty or
+// omitzero). Inline and ignored fields are never required.
+func isRequiredField(parent *types.Type, m types.Member, typePkgMap map[*types.Type]*apiPackage) bool {
+	tag := parseJSONTag(m)
+	if tag.skip || tag.inline {
+		return false
+	}
+	if required, ok := requirementMarker(m.CommentLines); ok {
+		return required
+	}
+	if required, ok := packageRequirement(parent, typePkgMap); ok && !required {
+		return false
+	}
+	return !tag.omitEmpty
+}
+
+// firstType returns the first of ts, or nil if there is none.
+func firstType(ts []*types.Type) *types.Type {
+	if len(ts) == 0 {
+		return nil
+	}
+	return ts[0]
+}
+
+// isOptionalField returns true if field m of struct type parent may be left
+// out of the serialized object. Inline and ignored fields are neither
+// required nor optional. See isRequiredField.
+func isOptionalField(parent *types.Type, m types.Member, typePkgMap map[*types.Type]*apiPackage) bool {
+	tag := parseJSONTag(m)
+	return !tag.skip && !tag.inline && !isRequiredField(parent, m, typePkgMap)
+}
+
+// validationMarkers are the names of the +kubebuilder:validation markers that
+// constrain the value of a field, in the order they are shown, with the
+// labels they are shown with.
+var validationMarkers = []struct{ name, label string }{
+	{"Minimum", "Minimum"},
+	{"ExclusiveMinimum", "Exclusive minimum"},
+	{"Maximum", "Maximum"},
+	{"ExclusiveMaximum", "Exclusive maximum"},
+	{"MultipleOf", "Multiple of"},
+	{"MinLength", "Min length"},
+	{"MaxLength", "Max length"},
+	{"Pattern", "Pattern"},
+	{"Format", "Format"},
+	{"MinItems", "Min items"},
+	{"MaxItems", "Max items"},
+	{"UniqueItems", "Unique items"},
+	{"MinProperties", "Min properties"},
+	{"MaxProperties", "Max properties"},
+}
+
+// constraint is a rule the value of a field must follow, set with a
+// +kubebuilder:validation marker.
+type constraint struct {
+	// Name is the name of the marker, e.g. "MinLength".
+	Name string
+	// Label is the name of the constraint for readers, e.g. "Min length".
+	Label string
+	Value string
+}
+
+// memberConstraints returns the constraints of the value of member m, set
+// with markers on the member or, like controller-gen does, on its type.
+func memberConstraints(m types.Member) []constraint {
+	t := m.Type
+	for t.Kind == types.Pointer {
+		t = t.Elem
+	}
+	var out []constraint
+	for _, v := range validationMarkers {
+		value, ok := commentTag(m.CommentLines, "kubebuilder:validation:"+v.name)
+		if !ok {
+			if value, ok = commentTag(t.CommentLines, "kubebuilder:validation:"+v.name); !ok {
+				continue
+			}
+		}
+		out = append(out, constraint{Name: v.name, Label: v.label, Value: markerString(value)})
+	}
+	return out
+}
+
+// markerString returns the value of a marker without the quotes or
+// backquotes around strings.
+func markerString(v string) string {
+	if len(v) >= 2 && (v[0] == '"' || v[0] == '`') && v[len(v)-1] == v[0] {
+		if s, err := strconv.Unquote(v); err == nil {
+			return s
+		}
+	}
+	return v
+}
+
+// allowedValue is one of the values a type or field is restricted to, either
+// by the Go constants of its type or by a +kubebuilder:validation:Enum marker.
+type allowedValue struct {
+	// Value is the value as it is written in YAML, e.g. "Running".
+	Value string
+	// Literal is the value as it is shown to readers, with strings quoted.
+	Literal string
+	// Constant is the Go constant with the value, or nil if the value is only
+	// listed in an Enum marker.
+	Constant *types.Type
+}
+
+// enumMarker returns the values of the +kubebuilder:validation:Enum marker in
+// the comment lines, or nil if there is none.
+func enumMarker(lines []string) []string {
+	v, ok := commentTag(lines, "kubebuilder:validation:Enum")
+	if !ok {
+		return nil
+	}
+	var out []string
+	for _, s := range strings.Split(v, ";") {
+		out = append(out, markerString(strings.TrimSpace(s)))
+	}
+	return out
+}
+
+// mergeAllowedValues returns the values of the Enum marker of t or of the
+// field restricting it, in their order, followed by those of the constants of
+// t that the marker does not list. Constants keep their descriptions.
+func mergeAllowedValues(t *types.Type, consts []*types.Type, marker []string) []allowedValue {
+	literal := func(v string) string {
+		if u := finalUnderlyingTypeOf(t); u.Kind == types.Builtin && u.Name.Name == "string" {
+			return strconv.Quote(v)
+		}
+		return v
+	}
+	byValue := make(map[string]*types.Type)
+	for _, c := range consts {
+		byValue[*c.ConstValue] = c
+	}
+
+	var out []allowedValue
+	listed := make(map[string]bool)
+	for _, v := range marker {
+		if !listed[v] {
+			listed[v] = true
+			out = append(out, allowedValue{Value: v, Literal: literal(v), Constant: byValue[v]})
+		}
+	}
+	for _, c := range consts {
+		if !listed[*c.ConstValue] {
+			out = append(out, allowedValue{Value: *c.ConstValue, Literal: literal(*c.ConstValue), Constant: c})
+		}
+	}
+	return out
+}
+
+// typeAllowedValues returns the allowed values of type t, from its constants
+// in pkg and its Enum marker, or nil if it is not an enum.
+func typeAllowedValues(t *types.Type, pkg *apiPackage) []allowedValue {
+	var consts []*types.Type
+	if pkg != nil {
+		consts = constantsOfType(t, pkg)
+	}
+	return mergeAllowedValues(t, consts, enumMarker(t.CommentLines))
+}
+
+// schemaAllowedValues returns the values of type t its schema accepts: like
+// controller-gen, only the values of its Enum marker if it has one, and
+// otherwise the values of its constants in pkg.
+func schemaAllowedValues(t *types.Type, pkg *apiPackage) []allowedValue {
+	values := typeAllowedValues(t, pkg)
+	marker := enumMarker(t.CommentLines)
+	if marker == nil {
+		return values
+	}
+	var out []allowedValue
+	for _, v := range values {
+		if containsString(marker, v.Value) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// memberAllowedValues returns the allowed values of member m if they are
+// restricted by an Enum marker on the field itself, or nil if they are not,
+// in which case its type lists them. The values of the marker that are
+// constants of the type of the field keep their descriptions.
+func memberAllowedValues(m types.Member, typePkgMap map[*types.Type]*apiPackage) []allowedValue {
+	marker := enumMarker(m.CommentLines)
+	if marker == nil {
+		return nil
+	}
+	t := tryDereference(m.Type)
+	var consts []*types.Type
+	if pkg := typePkgMap[t]; pkg != nil {
+		consts = constantsOfType(t, pkg)
+	}
+	var out []allowedValue
+	for _, v := range mergeAllowedValues(t, consts, marker) {
+		// a field may allow fewer values than its type has constants
+		if containsString(marker, v.Value) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// checkAllowedValues logs a warning for each value that is listed in the
+// Enum marker of a type with constants but is not one of them, or the other
+// way around, and for each value of the Enum marker of a field that is not a
+// constant of the type of the field.
+func checkAllowedValues(pkgs []*apiPackage, config generatorConfig) {
+	typePkgMap := extractTypeToPackageMap(pkgs)
+	for _, pkg := range pkgs {
+		for _, t := range visibleTypes(sortTypes(pkg.Types), config) {
+			// types with only one of the sources are not checked
+			if marker := enumMarker(t.CommentLines); marker != nil && len(constantsOfType(t, pkg)) > 0 {
+				for _, v := range typeAllowedValues(t, pkg) {
+					switch {
+					case v.Constant == nil:
+						klog.Warningf("type %s: value %s of the Enum marker is not a constant of the type", t.Name, v.Literal)
+					case !containsString(marker, v.Value):
+						klog.Warningf("type %s: value %s of constant %s is not in the Enum marker", t.Name, v.Literal, v.Constant.Name.Name)
+					}
+				}
+			}
+			for _, m := range t.Members {
+				if hiddenMember(m, config) {
+					continue
+				}
+				ft := tryDereference(m.Type)
+				if p := typePkgMap[ft]; p == nil || len(constantsOfType(ft, p)) == 0 {
+					continue
+				}
+				for _, v := range memberAllowedValues(m, typePkgMap) {
+					if v.Constant == nil {
+						klog.Warningf("field %s.%s: value %s of the Enum marker is not a constant of type %s", t.Name, m.Name, v.Literal, ft.Name)
+					}
+				}
+			}
+		}
+	}
+}
+
+// celRule is a CEL validation rule of a type or field, set with a
+// +kubebuilder:validation:XValidation marker.
+type celRule struct {
+	Rule              string `json:"rule"`
+	Message           string `json:"message,omitempty"`
+	MessageExpression string `json:"messageExpression,omitempty"`
+	Reason            string `json:"reason,omitempty"`
+	FieldPath         string `json:"fieldPath,omitempty"`
+	// Immutable is set for rules comparing the value with the previous one,
+	// which keep it from being changed.
+	Immutable bool `json:"-"`
+}
+
+const xValidationMarker = "+kubebuilder:validation:XValidation:"
+
+// immutabilityRule matches the CEL expressions that keep a value from being
+// changed.
+var immutabilityRule = regexp.MustCompile(`\bself\s*==\s*oldSelf\b|\boldSelf\s*==\s*self\b`)
+
+// celRules returns the CEL validation rules in the comment lines, in order.
+// Markers that cannot be parsed are logged and left out.
+func celRules(lines []string) []celRule {
+	var out []celRule
+	for _, l := range lines {
+		l = strings.TrimSpace(l)
+		if !strings.HasPrefix(l, xValidationMarker) {
+			continue
+		}
+		args, err := markerArgs(strings.TrimPrefix(l, xValidationMarker))
+		if err != nil {
+			klog.Warningf("cannot parse marker %q: %v", l, err)
+			continue
+		}
+		r := celRule{
+			Rule:              args["rule"],
+			Message:           args["message"],
+			MessageExpression: args["messageExpression"],
+			Reason:            args["reason"],
+			FieldPath:         args["fieldPath"],
+		}
+		if r.Rule == "" {
+			klog.Warningf("marker %q has no rule", l)
+			continue
+		}
+		r.Immutable = immutabilityRule.MatchString(r.Rule)
+		out = append(out, r)
+	}
+	return out
+}
+
+// Summary returns the message of the rule shown to readers.
+func (r celRule) Summary() string {
+	switch {
+	case r.Message != "":
+		return r.Message
+	case r.Immutable:
+		return "The value cannot be changed."
+	}
+	return "The value must satisfy the rule."
+}
+
+// markerArgs parses the arguments of a marker written as
+// key=value,key=value, where values are either quoted or backquoted strings,
+// or run up to the next comma.
+func markerArgs(s string) (map[string]string, error) {
+	out := make(map[string]string)
+	for s != "" {
+		i := strings.Index(s, "=")
+		if i < 0 {
+			return nil, fmt.Errorf("missing value of argument %q", s)
+		}
+		key, rest := strings.TrimSpace(s[:i]), s[i+1:]
+		var value string
+		if rest != "" && (rest[0] == '"' || rest[0] == '`') {
+			quoted, err := strconv.QuotedPrefix(rest)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value of argument %q: %w", key, err)
+			}
+			value, _ = strconv.Unquote(quoted)
+			rest = rest[len(quoted):]
+		} else if j := strings.Index(rest, ","); j >= 0 {
+			value, rest = rest[:j], rest[j:]
+		} else {
+			value, rest = rest, ""
+		}
+		out[key] = value
+		s = strings.TrimPrefix(strings.TrimSpace(rest), ",")
+	}
+	return out, nil
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// docusaurusSidebarFileName is the file the sidebar of the pages written with
+// -output-format=mdx and -out-dir is saved to.
+const docusaurusSidebarFileName = "sidebars.js"
+
+// docusaurusConfig configures the output of -output-format=mdx.
+type docusaurusConfig struct {
+	// SidebarID is the name of the sidebar in sidebars.js. Defaults to
+	// "apiReference".
+	SidebarID string `json:"sidebarID"`
+
+	// DocIDPrefix is prepended to the IDs of the pages listed in sidebars.js,
+	// e.g. "api/" when the output directory is docs/api.
+	DocIDPrefix string `json:"docIDPrefix"`
+}
+
+// docusaurusSidebarItem is an item of a Docusaurus sidebar: a doc, or a
+// category of items that links to a doc.
+type docusaurusSidebarItem struct {
+	Type  string                  `json:"type"`
+	ID    string                  `json:"id,omitempty"`
+	Label string                  `json:"label,omitempty"`
+	Link  *docusaurusSidebarItem  `json:"link,omitempty"`
+	Items []docusaurusSidebarItem `json:"items,omitempty"`
+}
+
+// docusaurusSidebar returns the sidebars.js fragment listing the pages of the
+// given layout: the API group/versions under the index page, with the pages
+// of their top-level API types nested under them.
+func docusaurusSidebar(pkgs []*apiPackage, config generatorConfig, layout *siteLayout) ([]byte, error) {
+	docID := func(page string) string {
+		return config.Docusaurus.DocIDPrefix + strings.TrimSuffix(page, layout.ext)
+	}
+	sidebarID := config.Docusaurus.SidebarID
+	if sidebarID == "" {
+		sidebarID = "apiReference"
+	}
+
+	root := docusaurusSidebarItem{
+		Type:  "category",
+		Label: "API Reference",
+		Link:  &docusaurusSidebarItem{Type: "doc", ID: docID(layout.indexPage())},
+	}
+	for _, pkg := range pkgs {
+		item := docusaurusSidebarItem{Type: "doc", ID: docID(layout.packagePage(pkg)), Label: pkg.identifier()}
+		if layout.pagePerKind {
+			for _, t := range kindTypes([]*apiPackage{pkg}, config) {
+				item.Items = append(item.Items, docusaurusSidebarItem{Type: "doc", ID: docID(layout.typePage(t)), Label: t.Name.Name})
+			}
+		}
+		if len(item.Items) > 0 {
+			item.Link = &docusaurusSidebarItem{Type: "doc", ID: item.ID}
+			item.Type, item.ID = "category", ""
+		}
+		root.Items = append(root.Items, item)
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintln(&b, "// Generated with gen-crd-api-reference-docs. DO NOT EDIT.")
+	fmt.Fprintln(&b)
+	fmt.Fprint(&b, "module.exports = ")
+	if err := writeJSON(&b, map[string][]docusaurusSidebarItem{sidebarID: {root}}); err != nil {
+		return nil, err
+	}
+	return append(bytes.TrimRight(b.Bytes(), "\n"), ";\n"...), nil
+}
+
+// renderCommentsMDX returns the comment lines as MDX source, escaping the
+// characters MDX would parse as JSX or expressions.
+func renderCommentsMDX(s []string, markdown bool) string {
+	if !markdown {
+		return mdxEscaper.Replace(renderCommentsMarkdown(s, false))
+	}
+	return mdxEscape(renderCommentsMarkdown(s, true))
+}
+
+// mdxText escapes the plain text s for MDX.
+func mdxText(s string) string { return mdxEscaper.Replace(markdownText(s)) }
+
+var mdxEscaper = strings.NewReplacer("{", `\{`, "}", `\}`)
+
+// mdxEscape escapes the Markdown source s for MDX, leaving code untouched.
+// MDX does not support indented code blocks, so they are turned into fenced
+// code blocks.
+func mdxEscape(s string) string {
+	var out []string
+	var fence string  // delimiter of the open fenced code block
+	var indented bool // whether an indented code block is open
+	prevBlank := true
+
+	for _, line := range strings.Split(s, "\n") {
+		if fence != "" {
+			if m := mdFence.FindStringSubmatch(line); m != nil && m[1] == fence {
+				fence = ""
+			}
+			out = append(out, line)
+			continue
+		}
+		if indented {
+			if mdIndentedCode.MatchString(line) || strings.TrimSpace(line) == "" {
+				out = append(out, strings.TrimPrefix(strings.TrimPrefix(line, "\t"), "    "))
+				continue
+			}
+			out = closeBlock(out, "```")
+			indented = false
+		}
+
+		switch {
+		case mdFence.MatchString(line):
+			fence = mdFence.FindStringSubmatch(line)[1]
+			out = append(out, line)
+		case prevBlank && mdIndentedCode.MatchString(line) && !mdBulletItem.MatchString(line) && !mdOrderedItem.MatchString(line):
+			indented = true
+			out = append(out, "```", strings.TrimPrefix(strings.TrimPrefix(line, "\t"), "    "))
+		default:
+			out = append(out, mdxInline(line))
+		}
+		prevBlank = strings.TrimSpace(line) == ""
+	}
+	if fence != "" {
+		out = append(out, fence)
+	}
+	if indented {
+		out = closeBlock(out, "```")
+	}
+	return strings.Join(out, "\n")
+}
+
+// mdxInline escapes a line of Markdown for MDX, leaving code spans untouched.
+// Autolinks are turned into links, since MDX parses them as JSX.
+func mdxInline(s string) string {
+	var b strings.Builder
+	last := 0
+	for _, loc := range mdCodeSpan.FindAllStringIndex(s, -1) {
+		b.WriteString(mdxInlineText(s[last:loc[0]]))
+		b.WriteString(s[loc[0]:loc[1]])
+		last = loc[1]
+	}
+	b.WriteString(mdxInlineText(s[last:]))
+	return b.String()
+}
+
+func mdxInlineText(s string) string {
+	var b strings.Builder
+	last := 0
+	for _, m := range mdAutoLink.FindAllStringSubmatchIndex(s, -1) {
+		b.WriteString(mdxTextEscaper.Replace(s[last:m[0]]))
+		url := s[m[2]:m[3]]
+		b.WriteString("[" + url + "](" + url + ")")
+		last = m[1]
+	}
+	b.WriteString(mdxTextEscaper.Replace(s[last:]))
+	return b.String()
+}
+
+var mdxTextEscaper = strings.NewReplacer("{", `\{`, "}", `\}`, "<", "&lt;")
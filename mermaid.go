@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/gengo/v2/types"
+)
+
+// Values of -class-diagrams.
+const (
+	classDiagramsPackage = "package"
+	classDiagramsKind    = "kind"
+)
+
+// packageDiagramTypes returns the types shown in the class diagram of an API
+// package: its visible struct and enum types.
+func packageDiagramTypes(pkg *apiPackage, c generatorConfig) []*types.Type {
+	var out []*types.Type
+	for _, t := range visibleTypes(sortTypes(pkg.Types), c) {
+		if t.Kind == types.Struct || len(typeAllowedValues(t, pkg)) > 0 {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// kindDiagramTypes returns the types shown in the class diagram of the
+// top-level API type t: t and the local struct and enum types its fields
+// refer to, in the order they are reached.
+func kindDiagramTypes(t *types.Type, c generatorConfig, typePkgMap map[*types.Type]*apiPackage) []*types.Type {
+	out := []*types.Type{t}
+	seen := map[*types.Type]bool{t: true}
+	for _, f := range walkFields(t, c, typePkgMap) {
+		elem, _ := fieldElemType(f.Member.Type)
+		named := tryDereference(f.Member.Type)
+		for _, v := range []*types.Type{elem, named} {
+			if seen[v] || !isLocalType(v, typePkgMap) {
+				continue
+			}
+			if v.Kind == types.Struct || len(typeAllowedValues(v, typePkgMap[v])) > 0 {
+				seen[v] = true
+				out = append(out, v)
+			}
+		}
+	}
+	return out
+}
+
+// mermaidClassDiagram returns a Mermaid class diagram of the given types: a
+// class with the fields of each struct type and the values of each enum type,
+// and an edge for each field of one of the types that refers to another,
+// labeled with the name of the field and its cardinality.
+func mermaidClassDiagram(ts []*types.Type, c generatorConfig, typePkgMap map[*types.Type]*apiPackage) string {
+	shown := make(map[*types.Type]bool)
+	for _, t := range ts {
+		shown[t] = true
+	}
+
+	var b strings.Builder
+	var edges []string
+	b.WriteString("classDiagram\n")
+	for _, t := range ts {
+		if t.Kind != types.Struct {
+			fmt.Fprintf(&b, "  class %s {\n    <<enumeration>>\n", t.Name.Name)
+			for _, v := range typeAllowedValues(t, typePkgMap[t]) {
+				fmt.Fprintf(&b, "    %s\n", v.Value)
+			}
+			b.WriteString("  }\n")
+			continue
+		}
+
+		members := serializedMembers(t, c)
+		if len(members) == 0 {
+			fmt.Fprintf(&b, "  class %s\n", t.Name.Name)
+			continue
+		}
+		fmt.Fprintf(&b, "  class %s {\n", t.Name.Name)
+		for _, sm := range members {
+			name := parseJSONTag(sm.Member).name
+			fmt.Fprintf(&b, "    +%s %s\n", mermaidTypeName(sm.Member.Type), name)
+
+			elem, suffix := fieldElemType(sm.Member.Type)
+			if named := tryDereference(sm.Member.Type); shown[named] && !shown[elem] {
+				elem = named
+			}
+			if !shown[elem] {
+				continue
+			}
+			edges = append(edges, fmt.Sprintf("  %s --> %q %s : %s%s", t.Name.Name, mermaidCardinality(sm.Parent, sm.Member, typePkgMap), elem.Name.Name, name, suffix))
+		}
+		b.WriteString("  }\n")
+	}
+	for _, e := range edges {
+		b.WriteString(e + "\n")
+	}
+	return b.String()
+}
+
+// mermaidCardinality returns the number of values of the type of field m of
+// struct type parent that an object has through the field.
+func mermaidCardinality(parent *types.Type, m types.Member, typePkgMap map[*types.Type]*apiPackage) string {
+	_, suffix := fieldElemType(m.Type)
+	switch {
+	case suffix != "":
+		return "0..*"
+	case isRequiredField(parent, m, typePkgMap) && m.Type.Kind != types.Pointer:
+		return "1"
+	}
+	return "0..1"
+}
+
+// mermaidTypeName returns the name of type t in the members of a class. The
+// package of named types is left out, and maps are written with Go syntax
+// since Mermaid generics cannot have more than one type parameter.
+func mermaidTypeName(t *types.Type) string {
+	switch t.Kind {
+	case types.Pointer:
+		return mermaidTypeName(t.Elem)
+	case types.Slice, types.Array:
+		return "[]" + mermaidTypeName(t.Elem)
+	case types.Map:
+		return fmt.Sprintf("map[%s]%s", mermaidTypeName(t.Key), mermaidTypeName(t.Elem))
+	}
+	return t.Name.Name
+}
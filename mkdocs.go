@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// mkdocsNavFileName is the file the nav of the pages written with
+// -output-format=mkdocs and -out-dir is saved to.
+const mkdocsNavFileName = "nav.yml"
+
+// mkdocsConfig configures the output of -output-format=mkdocs.
+type mkdocsConfig struct {
+	// NavPathPrefix is prepended to the paths of the pages listed in nav.yml,
+	// e.g. "api/" when the output directory is docs/api.
+	NavPathPrefix string `json:"navPathPrefix"`
+}
+
+// mkdocsNav returns the nav: snippet for mkdocs.yml listing the pages of the
+// given layout, with the pages of top-level API types nested in the section
+// of their API group/version.
+func mkdocsNav(pkgs []*apiPackage, config generatorConfig, layout *siteLayout) []byte {
+	navPath := func(page string) string { return yamlString(config.MkDocs.NavPathPrefix + page) }
+
+	var b bytes.Buffer
+	fmt.Fprintln(&b, "# Generated with gen-crd-api-reference-docs. DO NOT EDIT.")
+	fmt.Fprintln(&b, "nav:")
+	fmt.Fprintln(&b, "  - API Reference:")
+	fmt.Fprintf(&b, "      - %s\n", navPath(layout.indexPage()))
+	for _, pkg := range pkgs {
+		kinds := kindTypes([]*apiPackage{pkg}, config)
+		if !layout.pagePerKind || len(kinds) == 0 {
+			fmt.Fprintf(&b, "      - %s: %s\n", yamlString(pkg.identifier()), navPath(layout.packagePage(pkg)))
+			continue
+		}
+		fmt.Fprintf(&b, "      - %s:\n", yamlString(pkg.identifier()))
+		fmt.Fprintf(&b, "          - %s\n", navPath(layout.packagePage(pkg)))
+		for _, t := range kinds {
+			fmt.Fprintf(&b, "          - %s: %s\n", yamlString(t.Name.Name), navPath(layout.typePage(t)))
+		}
+	}
+	return b.Bytes()
+}
+
+// splitDeprecation splits the comment lines into the paragraph starting with
+// "Deprecated:" (following the Go convention) and the rest of them.
+func splitDeprecation(s []string) (rest, deprecation []string) {
+	var inDeprecation bool
+	for _, v := range s {
+		switch {
+		case strings.TrimSpace(v) == "":
+			inDeprecation = false
+		case len(deprecation) == 0 && strings.HasPrefix(strings.TrimSpace(v), "Deprecated:"):
+			inDeprecation = true
+		}
+		if inDeprecation {
+			deprecation = append(deprecation, v)
+		} else {
+			rest = append(rest, v)
+		}
+	}
+	return rest, deprecation
+}
+
+// mkdocsDeprecationNotice returns the Markdown text of the "Deprecated:"
+// paragraph of the comment lines, if any.
+func mkdocsDeprecationNotice(s []string, markdown bool) string {
+	_, d := splitDeprecation(s)
+	if len(d) == 0 {
+		return ""
+	}
+	d[0] = strings.TrimPrefix(strings.TrimSpace(d[0]), "Deprecated:")
+	return renderCommentsMarkdown(d, markdown)
+}
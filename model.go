@@ -0,0 +1,171 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"k8s.io/gengo/v2/types"
+)
+
+// apiModelVersion is the version of the document written with
+// -output-format=json. It changes only when the document changes in a way
+// that is not backwards compatible.
+const apiModelVersion = "gen-crd-api-reference-docs/v1"
+
+// apiModel is the parsed API, as written with -output-format=json.
+type apiModel struct {
+	Version  string            `json:"version"`
+	Packages []apiModelPackage `json:"packages"`
+}
+
+type apiModelPackage struct {
+	Group      string         `json:"group"`
+	Version    string         `json:"version"`
+	Identifier string         `json:"identifier"`
+	GoPackages []string       `json:"goPackages"`
+	Comment    string         `json:"comment,omitempty"`
+	Types      []apiModelType `json:"types"`
+}
+
+type apiModelType struct {
+	Name       string `json:"name"`
+	Identifier string `json:"identifier"`
+	// Kind is the kind of the Go type (e.g. Struct, Alias).
+	Kind string `json:"kind"`
+	// Exported is true for top-level API types (e.g. the ones with +genclient).
+	Exported   bool               `json:"exported"`
+	Anchor     string             `json:"anchor"`
+	Link       string             `json:"link"`
+	Underlying *apiModelTypeRef   `json:"underlying,omitempty"`
+	Comment    string             `json:"comment,omitempty"`
+	Members    []apiModelMember   `json:"members,omitempty"`
+	Constants  []apiModelConstant `json:"constants,omitempty"`
+	References []apiModelTypeRef  `json:"references,omitempty"`
+}
+
+type apiModelMember struct {
+	Name      string          `json:"name"`
+	FieldName string          `json:"fieldName"`
+	Type      apiModelTypeRef `json:"type"`
+	Optional  bool            `json:"optional"`
+	Embedded  bool            `json:"embedded"`
+	// Default is the default value of the field, decoded from JSON.
+	Default interface{} `json:"default,omitempty"`
+	Comment string      `json:"comment,omitempty"`
+}
+
+// apiModelTypeRef is a reference to a type from a member, an alias or the
+// list of types a type appears on.
+type apiModelTypeRef struct {
+	Identifier  string `json:"identifier"`
+	DisplayName string `json:"displayName"`
+	Local       bool   `json:"local"`
+	Link        string `json:"link,omitempty"`
+}
+
+type apiModelConstant struct {
+	Name    string `json:"name"`
+	Value   string `json:"value"`
+	Comment string `json:"comment,omitempty"`
+}
+
+// buildAPIModel resolves the given API packages into an apiModel, leaving out
+// the types and members hidden by the config.
+func buildAPIModel(pkgs []*apiPackage, config generatorConfig) (*apiModel, error) {
+	references := findTypeReferences(pkgs)
+	typePkgMap := extractTypeToPackageMap(pkgs)
+
+	typeRef := func(t *types.Type) (apiModelTypeRef, error) {
+		link, err := linkForType(t, config, typePkgMap)
+		if err != nil {
+			return apiModelTypeRef{}, fmt.Errorf("error getting link for type=%s: %w", t.Name, err)
+		}
+		return apiModelTypeRef{
+			Identifier:  typeIdentifier(t),
+			DisplayName: typeDisplayName(t, config, typePkgMap),
+			Local:       isLocalType(t, typePkgMap),
+			Link:        link,
+		}, nil
+	}
+
+	out := &apiModel{Version: apiModelVersion, Packages: []apiModelPackage{}}
+	for _, pkg := range pkgs {
+		p := apiModelPackage{
+			Group:      pkg.apiGroup,
+			Version:    pkg.apiVersion,
+			Identifier: pkg.identifier(),
+			Comment:    renderCommentsMarkdown(pkg.GoPackages[0].DocComments, true),
+			Types:      []apiModelType{},
+		}
+		for _, gp := range pkg.GoPackages {
+			p.GoPackages = append(p.GoPackages, gp.Path)
+		}
+
+		for _, t := range visibleTypes(sortTypes(pkg.Types), config) {
+			ref, err := typeRef(t)
+			if err != nil {
+				return nil, err
+			}
+			mt := apiModelType{
+				Name:       t.Name.Name,
+				Identifier: ref.Identifier,
+				Kind:       string(t.Kind),
+				Exported:   isExportedType(t),
+				Anchor:     anchorIDForLocalType(t, typePkgMap),
+				Link:       ref.Link,
+				Comment:    renderCommentsMarkdown(t.CommentLines, true),
+			}
+			if t.Kind == types.Alias {
+				u, err := typeRef(t.Underlying)
+				if err != nil {
+					return nil, err
+				}
+				mt.Underlying = &u
+			}
+			for _, m := range t.Members {
+				if hiddenMember(m, config) {
+					continue
+				}
+				mref, err := typeRef(m.Type)
+				if err != nil {
+					return nil, err
+				}
+				defaultValue, _ := memberDefaultValue(m)
+				mt.Members = append(mt.Members, apiModelMember{
+					Name:      m.Name,
+					FieldName: fieldName(m),
+					Type:      mref,
+					Optional:  isOptionalField(t, m, typePkgMap),
+					Embedded:  fieldEmbedded(m),
+					Default:   defaultValue,
+					Comment:   renderCommentsMarkdown(m.CommentLines, true),
+				})
+			}
+			for _, c := range constantsOfType(t, pkg) {
+				mt.Constants = append(mt.Constants, apiModelConstant{
+					Name:    c.Name.Name,
+					Value:   *c.ConstValue,
+					Comment: renderCommentsMarkdown(c.CommentLines, true),
+				})
+			}
+			for _, r := range typeReferences(t, config, references) {
+				rref, err := typeRef(r)
+				if err != nil {
+					return nil, err
+				}
+				mt.References = append(mt.References, rref)
+			}
+			p.Types = append(p.Types, mt)
+		}
+		out.Packages = append(out.Packages, p)
+	}
+	return out, nil
+}
+
+func renderJSON(w io.Writer, pkgs []*apiPackage, config generatorConfig) error {
+	model, err := buildAPIModel(pkgs, config)
+	if err != nil {
+		return err
+	}
+	return writeJSON(w, model)
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"bytes"
+	"io"
+)
+
+// openAPIKindSchema is the OpenAPI v3 structural schema of a top-level API
+// type, to be used as the schema of the version in a CustomResourceDefinition.
+type openAPIKindSchema struct {
+	Group           string      `json:"group"`
+	Version         string      `json:"version"`
+	Kind            string      `json:"kind"`
+	OpenAPIV3Schema *jsonSchema `json:"openAPIV3Schema"`
+}
+
+func openAPIKindSchemas(pkgs []*apiPackage, config generatorConfig) []openAPIKindSchema {
+	typePkgMap := extractTypeToPackageMap(pkgs)
+
+	out := []openAPIKindSchema{}
+	for _, t := range kindTypes(pkgs, config) {
+		out = append(out, openAPIKindSchema{
+			Group:           typePkgMap[t].apiGroup,
+			Version:         typePkgMap[t].apiVersion,
+			Kind:            t.Name.Name,
+			OpenAPIV3Schema: newStructuralSchemaBuilder(config, typePkgMap).kindSchema(t),
+		})
+	}
+	return out
+}
+
+// renderOpenAPI writes the structural schemas of all top-level API types.
+func renderOpenAPI(w io.Writer, pkgs []*apiPackage, config generatorConfig) error {
+	return writeJSON(w, openAPIKindSchemas(pkgs, config))
+}
+
+// renderOpenAPIFiles returns the structural schema of each top-level API type,
+// keyed by its path relative to the output directory. The files can be pasted
+// as the openAPIV3Schema of the corresponding version in a
+// CustomResourceDefinition.
+func renderOpenAPIFiles(pkgs []*apiPackage, config generatorConfig) (map[string][]byte, error) {
+	typePkgMap := extractTypeToPackageMap(pkgs)
+	out := make(map[string][]byte)
+
+	for _, t := range kindTypes(pkgs, config) {
+		var buf bytes.Buffer
+		if err := writeJSON(&buf, newStructuralSchemaBuilder(config, typePkgMap).kindSchema(t)); err != nil {
+			return nil, err
+		}
+		out[jsonSchemaFileName(t, typePkgMap)] = buf.Bytes()
+	}
+	return out, nil
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"k8s.io/gengo/v2/types"
+)
+
+// rstHeadingChars are the characters used to underline the section titles of
+// each heading level. They must be used consistently across pages, since
+// reStructuredText infers the level of a section from its underline.
+var rstHeadingChars = map[int]string{1: "#", 2: "=", 3: "-", 4: "~"}
+
+// rstHeading returns a section title with a label to refer to it with :ref:,
+// if id is not empty.
+func rstHeading(level int, text, id string) string {
+	var b strings.Builder
+	if id != "" {
+		fmt.Fprintf(&b, ".. _%s:\n\n", id)
+	}
+	fmt.Fprintf(&b, "%s\n%s", text, strings.Repeat(rstHeadingChars[level], len(text)))
+	return b.String()
+}
+
+// rstLiteral formats s as an inline literal.
+func rstLiteral(s string) string { return "``" + s + "``" }
+
+// rstTypeLink returns the reStructuredText markup for a reference to type t,
+// given the link to it: a :ref: to the label of local types, a hyperlink for
+// recognized external types, or the plain display name otherwise.
+func rstTypeLink(t *types.Type, link string, c generatorConfig, typePkgMap map[*types.Type]*apiPackage) string {
+	name := typeDisplayName(t, c, typePkgMap)
+
+	switch {
+	case isLocalType(t, typePkgMap):
+		return rstRef(name, sanitizeAnchorID(anchorIDForLocalType(tryDereference(t), typePkgMap)))
+	case link != "":
+		return "`" + rstRefTitleEscaper.Replace(name) + " <" + link + ">`__"
+	}
+	return rstLiteral(name)
+}
+
+// rstRef returns a :ref: to label with the given title.
+func rstRef(title, label string) string {
+	return ":ref:`" + rstRefTitleEscaper.Replace(title) + " <" + label + ">`"
+}
+
+var rstRefTitleEscaper = strings.NewReplacer("`", "\\`", "<", "\\<")
+
+// rstListTableCell indents the continuation lines of s so it can be used as
+// the content of a list-table cell, written in the templates after "   * - "
+// or "     - ".
+func rstListTableCell(s string) string {
+	return strings.TrimSpace(indent(7, strings.TrimSpace(s)))
+}
+
+// renderCommentsRST returns the comment lines as reStructuredText, converting
+// them from Markdown unless markdown is false.
+func renderCommentsRST(s []string, markdown bool) string {
+	s = filterCommentTags(s)
+	doc := strings.TrimSpace(strings.Join(s, "\n"))
+
+	if markdown {
+		return markdownToRST(doc)
+	}
+	return rstEscaper.Replace(doc)
+}
+
+// rstText escapes the plain text s for reStructuredText.
+func rstText(s string) string { return rstEscaper.Replace(s) }
+
+var rstEscaper = strings.NewReplacer(`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "|", `\|`)
+
+// markdownToRST converts the subset of Markdown that shows up in godoc
+// comments (paragraphs, headings, lists, code and links) to
+// reStructuredText.
+func markdownToRST(s string) string {
+	var out []string
+	var fence string  // delimiter of the open fenced code block
+	var indented bool // whether an indented code block is open
+	prevBlank := true
+	prevListItem := false
+
+	for _, line := range strings.Split(s, "\n") {
+		if fence != "" {
+			if m := mdFence.FindStringSubmatch(line); m != nil && m[1] == fence {
+				out = append(out, "")
+				fence = ""
+				prevBlank = true
+			} else {
+				out = append(out, indent(3, line))
+			}
+			continue
+		}
+		if indented {
+			if mdIndentedCode.MatchString(line) || strings.TrimSpace(line) == "" {
+				out = append(out, line)
+				continue
+			}
+			if strings.TrimSpace(out[len(out)-1]) != "" {
+				out = append(out, "")
+			}
+			indented = false
+		}
+
+		// lists must be separated from the paragraph before them.
+		listItem := mdBulletItem.MatchString(line) || mdOrderedItem.MatchString(line)
+		if listItem && !prevBlank && !prevListItem {
+			out = append(out, "")
+		}
+
+		switch {
+		case mdFence.MatchString(line):
+			m := mdFence.FindStringSubmatch(line)
+			fence = m[1]
+			if !prevBlank {
+				out = append(out, "")
+			}
+			if m[2] != "" {
+				out = append(out, ".. code-block:: "+m[2], "")
+			} else {
+				out = append(out, "::", "")
+			}
+		case prevBlank && mdIndentedCode.MatchString(line) && !mdBulletItem.MatchString(line) && !mdOrderedItem.MatchString(line):
+			indented = true
+			out = append(out, "::", "", line)
+		case mdHeading.MatchString(line):
+			// headings in comments must not interfere with the sections
+			// generated for packages and types.
+			if !prevBlank {
+				out = append(out, "")
+			}
+			out = append(out, ".. rubric:: "+rstInline(mdHeading.FindStringSubmatch(line)[2]), "")
+			line = ""
+		case mdBulletItem.MatchString(line):
+			m := mdBulletItem.FindStringSubmatch(line)
+			out = append(out, m[1]+"- "+rstInline(m[2]))
+		default:
+			out = append(out, rstInline(line))
+		}
+		prevBlank = strings.TrimSpace(out[len(out)-1]) == ""
+		prevListItem = listItem || (prevListItem && !prevBlank)
+	}
+	return strings.TrimRight(strings.Join(out, "\n"), "\n ")
+}
+
+// rstInlineMarkup matches the inline Markdown markup that is converted to
+// reStructuredText markup rather than escaped: code spans, links and
+// autolinks.
+var rstInlineMarkup = regexp.MustCompile(mdCodeSpan.String() + "|" + mdLink.String() + "|" + mdAutoLink.String())
+
+// rstInline converts the inline Markdown markup in s, escaping the rest of
+// the text.
+func rstInline(s string) string {
+	var b strings.Builder
+	last := 0
+	for _, loc := range rstInlineMarkup.FindAllStringIndex(s, -1) {
+		b.WriteString(rstInlineText(s[last:loc[0]]))
+		v := s[loc[0]:loc[1]]
+		var markup string
+		switch {
+		case strings.HasPrefix(v, "`"):
+			markup = rstLiteral(strings.TrimSpace(strings.Trim(v, "`")))
+		case strings.HasPrefix(v, "["):
+			m := mdLink.FindStringSubmatch(v)
+			markup = "`" + rstRefTitleEscaper.Replace(m[1]) + " <" + m[2] + ">`__"
+		default:
+			markup = mdAutoLink.FindStringSubmatch(v)[1]
+		}
+		// inline markup must be separated from the surrounding words, which
+		// an escaped space does without showing up in the output.
+		if loc[0] > 0 && isWordChar(s[loc[0]-1]) {
+			b.WriteString(`\ `)
+		}
+		b.WriteString(markup)
+		if loc[1] < len(s) && isWordChar(s[loc[1]]) {
+			b.WriteString(`\ `)
+		}
+		last = loc[1]
+	}
+	b.WriteString(rstInlineText(s[last:]))
+	return b.String()
+}
+
+func rstInlineText(s string) string {
+	// emphasis is swapped for placeholders first, so that the markers are
+	// not escaped along with the rest of the text.
+	s = mdStrong.ReplaceAllString(s, "\x00$1$2\x00")
+	s = mdEmphasis.ReplaceAllString(s, "${1}\x01${2}\x01")
+	s = rstEscaper.Replace(s)
+	return strings.NewReplacer("\x00", "**", "\x01", "*").Replace(s)
+}
+
+func isWordChar(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
@@ -0,0 +1,516 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"k8s.io/gengo/v2/types"
+)
+
+const jsonSchemaDraft = "http://json-schema.org/draft-07/schema#"
+
+// jsonSchema is the subset of JSON Schema used to describe API types.
+type jsonSchema struct {
+	Schema               string                 `json:"$schema,omitempty"`
+	Ref                  string                 `json:"$ref,omitempty"`
+	Title                string                 `json:"title,omitempty"`
+	Description          string                 `json:"description,omitempty"`
+	MarkdownDescription  string                 `json:"markdownDescription,omitempty"`
+	Type                 string                 `json:"type,omitempty"`
+	Format               string                 `json:"format,omitempty"`
+	Pattern              string                 `json:"pattern,omitempty"`
+	Minimum              *float64               `json:"minimum,omitempty"`
+	Maximum              *float64               `json:"maximum,omitempty"`
+	ExclusiveMinimum     interface{}            `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum     interface{}            `json:"exclusiveMaximum,omitempty"`
+	MultipleOf           *float64               `json:"multipleOf,omitempty"`
+	MinLength            *int64                 `json:"minLength,omitempty"`
+	MaxLength            *int64                 `json:"maxLength,omitempty"`
+	MinItems             *int64                 `json:"minItems,omitempty"`
+	MaxItems             *int64                 `json:"maxItems,omitempty"`
+	UniqueItems          bool                   `json:"uniqueItems,omitempty"`
+	MinProperties        *int64                 `json:"minProperties,omitempty"`
+	MaxProperties        *int64                 `json:"maxProperties,omitempty"`
+	Const                interface{}            `json:"const,omitempty"`
+	Default              interface{}            `json:"default,omitempty"`
+	Enum                 []interface{}          `json:"enum,omitempty"`
+	Properties           map[string]*jsonSchema `json:"properties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	Items                *jsonSchema            `json:"items,omitempty"`
+	AdditionalProperties *jsonSchema            `json:"additionalProperties,omitempty"`
+	AllOf                []*jsonSchema          `json:"allOf,omitempty"`
+	AnyOf                []*jsonSchema          `json:"anyOf,omitempty"`
+	OneOf                []*jsonSchema          `json:"oneOf,omitempty"`
+	Definitions          map[string]*jsonSchema `json:"definitions,omitempty"`
+
+	XIntOrString           bool `json:"x-kubernetes-int-or-string,omitempty"`
+	XPreserveUnknownFields bool `json:"x-kubernetes-preserve-unknown-fields,omitempty"`
+	XEmbeddedResource      bool `json:"x-kubernetes-embedded-resource,omitempty"`
+	// XValidations are the CEL validation rules of the value.
+	XValidations []celRule `json:"x-kubernetes-validations,omitempty"`
+}
+
+// wellKnownTypeSchemas are the schemas of types that are serialized
+// differently than their Go type suggests.
+var wellKnownTypeSchemas = map[string]func() *jsonSchema{
+	"k8s.io/apimachinery/pkg/apis/meta/v1.Time":      func() *jsonSchema { return &jsonSchema{Type: "string", Format: "date-time"} },
+	"k8s.io/apimachinery/pkg/apis/meta/v1.MicroTime": func() *jsonSchema { return &jsonSchema{Type: "string", Format: "date-time"} },
+	"k8s.io/apimachinery/pkg/apis/meta/v1.Duration":  func() *jsonSchema { return &jsonSchema{Type: "string"} },
+	"k8s.io/apimachinery/pkg/api/resource.Quantity": func() *jsonSchema {
+		s := intOrStringSchema()
+		s.Pattern = `^(\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))))?$`
+		return s
+	},
+	"k8s.io/apimachinery/pkg/util/intstr.IntOrString":               intOrStringSchema,
+	"k8s.io/apimachinery/pkg/runtime.RawExtension":                  func() *jsonSchema { return &jsonSchema{Type: "object", XPreserveUnknownFields: true} },
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1.JSON": func() *jsonSchema { return &jsonSchema{XPreserveUnknownFields: true} },
+}
+
+// wellKnownStructuralTypeSchemas override wellKnownTypeSchemas in structural
+// schemas, following what controller-gen generates for these types.
+var wellKnownStructuralTypeSchemas = map[string]func() *jsonSchema{
+	"k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta": func() *jsonSchema { return &jsonSchema{Type: "object"} },
+}
+
+func intOrStringSchema() *jsonSchema {
+	return &jsonSchema{AnyOf: []*jsonSchema{{Type: "integer"}, {Type: "string"}}, XIntOrString: true}
+}
+
+// jsonTag describes how a struct member is serialized by encoding/json.
+type jsonTag struct {
+	name      string
+	inline    bool
+	omitEmpty bool // omitempty or omitzero
+	skip      bool
+}
+
+func parseJSONTag(m types.Member) jsonTag {
+	v, ok := reflect.StructTag(m.Tags).Lookup("json")
+	if v == "-" || (!m.Embedded && unicode.IsLower(rune(m.Name[0]))) {
+		return jsonTag{skip: true}
+	}
+	parts := strings.Split(v, ",")
+	tag := jsonTag{name: parts[0]}
+	for _, opt := range parts[1:] {
+		switch opt {
+		case "inline":
+			tag.inline = true
+		case "omitempty", "omitzero":
+			tag.omitEmpty = true
+		}
+	}
+	// encoding/json inlines the fields of untagged embedded structs
+	if m.Embedded && (!ok || tag.name == "") {
+		tag.inline = true
+	}
+	if tag.name == "" {
+		tag.name = m.Name
+	}
+	return tag
+}
+
+// schemaBuilder builds JSON schemas for API types, collecting the schemas of
+// the named struct types they refer to as definitions.
+//
+// When structural is set, it builds OpenAPI v3 structural schemas as used in
+// CustomResourceDefinitions instead: every type is inlined, and recursive
+// references are cut off with x-kubernetes-preserve-unknown-fields.
+type schemaBuilder struct {
+	config     generatorConfig
+	typePkgMap map[*types.Type]*apiPackage
+	defs       map[string]*jsonSchema
+
+	structural bool
+	visiting   map[*types.Type]bool
+}
+
+func newSchemaBuilder(config generatorConfig, typePkgMap map[*types.Type]*apiPackage) *schemaBuilder {
+	return &schemaBuilder{config: config, typePkgMap: typePkgMap, defs: make(map[string]*jsonSchema)}
+}
+
+func newStructuralSchemaBuilder(config generatorConfig, typePkgMap map[*types.Type]*apiPackage) *schemaBuilder {
+	return &schemaBuilder{config: config, typePkgMap: typePkgMap, structural: true, visiting: make(map[*types.Type]bool)}
+}
+
+// definitionName returns the key t is stored under in the definitions.
+func definitionName(t *types.Type) string {
+	return strings.Replace(t.Name.String(), "/", ".", -1)
+}
+
+// schemaForType returns the schema for t, referring to named struct types
+// through definitions.
+func (b *schemaBuilder) schemaForType(t *types.Type) *jsonSchema {
+	if f, ok := wellKnownStructuralTypeSchemas[t.Name.String()]; ok && b.structural {
+		return f()
+	}
+	if f, ok := wellKnownTypeSchemas[t.Name.String()]; ok {
+		return f()
+	}
+
+	switch t.Kind {
+	case types.Pointer:
+		return b.schemaForType(t.Elem)
+	case types.Slice, types.Array:
+		if e := t.Elem; e.Kind == types.Builtin && (e.Name.Name == "byte" || e.Name.Name == "uint8") {
+			return &jsonSchema{Type: "string", Format: "byte"}
+		}
+		return &jsonSchema{Type: "array", Items: b.schemaForType(t.Elem)}
+	case types.Map:
+		return &jsonSchema{Type: "object", AdditionalProperties: b.schemaForType(t.Elem)}
+	case types.Builtin:
+		return builtinSchema(t)
+	case types.Alias:
+		s := b.schemaForType(t.Underlying)
+		for _, v := range schemaAllowedValues(t, b.typePkgMap[t]) {
+			s.Enum = append(s.Enum, allowedValueJSON(t, v))
+		}
+		s.XValidations = append(s.XValidations, celRules(t.CommentLines)...)
+		return s
+	case types.Struct:
+		if b.structural {
+			if b.visiting[t] {
+				return &jsonSchema{Type: "object", XPreserveUnknownFields: true}
+			}
+			b.visiting[t] = true
+			defer delete(b.visiting, t)
+			return b.structSchema(t)
+		}
+		name := definitionName(t)
+		if _, ok := b.defs[name]; !ok {
+			b.defs[name] = nil // placeholder for recursive types
+			b.defs[name] = b.structSchema(t)
+		}
+		return &jsonSchema{Ref: "#/definitions/" + name}
+	}
+	// interfaces and anything else that cannot be described accept any value
+	return &jsonSchema{XPreserveUnknownFields: true}
+}
+
+func builtinSchema(t *types.Type) *jsonSchema {
+	switch t.Name.Name {
+	case "string":
+		return &jsonSchema{Type: "string"}
+	case "bool":
+		return &jsonSchema{Type: "boolean"}
+	case "int32", "uint32", "int64", "uint64":
+		return &jsonSchema{Type: "integer", Format: t.Name.Name}
+	case "int", "int8", "int16", "uint", "uint8", "uint16", "byte":
+		return &jsonSchema{Type: "integer"}
+	case "float32":
+		return &jsonSchema{Type: "number", Format: "float"}
+	case "float64":
+		return &jsonSchema{Type: "number", Format: "double"}
+	}
+	return &jsonSchema{}
+}
+
+// constantValue returns the value of constant c as it is written in JSON.
+func constantValue(c *types.Type) interface{} {
+	u := finalUnderlyingTypeOf(c)
+	if u.Kind == types.Builtin && u.Name.Name != "string" {
+		if v, err := strconv.ParseFloat(*c.ConstValue, 64); err == nil {
+			return v
+		}
+	}
+	return *c.ConstValue
+}
+
+// allowedValueJSON returns the allowed value v of type t as a JSON value.
+func allowedValueJSON(t *types.Type, v allowedValue) interface{} {
+	if v.Constant != nil {
+		return constantValue(v.Constant)
+	}
+	if u := finalUnderlyingTypeOf(t); u.Kind == types.Builtin && u.Name.Name != "string" {
+		if f, err := strconv.ParseFloat(v.Value, 64); err == nil {
+			return f
+		}
+	}
+	return v.Value
+}
+
+// structSchema returns the object schema for a struct, with the fields of
+// inlined members merged in.
+func (b *schemaBuilder) structSchema(t *types.Type) *jsonSchema {
+	s := &jsonSchema{
+		Type:        "object",
+		Description: plainTextComments(t.CommentLines),
+		Properties:  make(map[string]*jsonSchema),
+	}
+	if !b.structural {
+		s.MarkdownDescription = renderCommentsMarkdown(t.CommentLines, true)
+	}
+	s.XValidations = celRules(t.CommentLines)
+	b.addMembers(s, t)
+	if len(s.Properties) == 0 {
+		s.Properties = nil
+	}
+	return s
+}
+
+func (b *schemaBuilder) addMembers(s *jsonSchema, t *types.Type) {
+	for _, m := range t.Members {
+		tag := parseJSONTag(m)
+		if tag.skip {
+			continue
+		}
+		if tag.inline {
+			if u := tryDereference(m.Type); u.Kind == types.Struct {
+				b.addMembers(s, u)
+				continue
+			}
+		}
+		s.Properties[tag.name] = b.memberSchema(m)
+		if isRequiredField(t, m, b.typePkgMap) {
+			s.Required = append(s.Required, tag.name)
+		}
+	}
+}
+
+func (b *schemaBuilder) memberSchema(m types.Member) *jsonSchema {
+	s := b.schemaForType(m.Type)
+	if s.Ref != "" {
+		// siblings of $ref are ignored, so wrap it to attach a description.
+		s = &jsonSchema{AllOf: []*jsonSchema{s}}
+	}
+	s.Description = plainTextComments(m.CommentLines)
+	s.Default, _ = memberDefaultValue(m)
+	s.XValidations = append(s.XValidations, celRules(m.CommentLines)...)
+	b.addConstraints(s, memberConstraints(m))
+	if values := memberAllowedValues(m, b.typePkgMap); values != nil {
+		s.Enum = nil
+		for _, v := range values {
+			s.Enum = append(s.Enum, allowedValueJSON(tryDereference(m.Type), v))
+		}
+	}
+	if !b.structural {
+		s.MarkdownDescription = renderCommentsMarkdown(m.CommentLines, true)
+	}
+	return s
+}
+
+// addConstraints sets the keywords of the validation markers cs on s,
+// ignoring markers with values that do not parse.
+func (b *schemaBuilder) addConstraints(s *jsonSchema, cs []constraint) {
+	number := func(v string) *float64 {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return nil
+		}
+		return &f
+	}
+	count := func(v string) *int64 {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n < 0 {
+			return nil
+		}
+		return &n
+	}
+	var exclusiveMin, exclusiveMax bool
+	for _, c := range cs {
+		switch c.Name {
+		case "Minimum":
+			s.Minimum = number(c.Value)
+		case "Maximum":
+			s.Maximum = number(c.Value)
+		case "ExclusiveMinimum":
+			exclusiveMin, _ = strconv.ParseBool(c.Value)
+		case "ExclusiveMaximum":
+			exclusiveMax, _ = strconv.ParseBool(c.Value)
+		case "MultipleOf":
+			s.MultipleOf = number(c.Value)
+		case "MinLength":
+			s.MinLength = count(c.Value)
+		case "MaxLength":
+			s.MaxLength = count(c.Value)
+		case "Pattern":
+			s.Pattern = c.Value
+		case "Format":
+			s.Format = c.Value
+		case "MinItems":
+			s.MinItems = count(c.Value)
+		case "MaxItems":
+			s.MaxItems = count(c.Value)
+		case "UniqueItems":
+			s.UniqueItems, _ = strconv.ParseBool(c.Value)
+		case "MinProperties":
+			s.MinProperties = count(c.Value)
+		case "MaxProperties":
+			s.MaxProperties = count(c.Value)
+		}
+	}
+	// in OpenAPI v3 the exclusive bounds are flags on the bounds
+	if b.structural {
+		if exclusiveMin && s.Minimum != nil {
+			s.ExclusiveMinimum = true
+		}
+		if exclusiveMax && s.Maximum != nil {
+			s.ExclusiveMaximum = true
+		}
+		return
+	}
+	// in draft-07 the exclusive bounds are numbers replacing the bounds
+	if exclusiveMin && s.Minimum != nil {
+		s.ExclusiveMinimum, s.Minimum = *s.Minimum, nil
+	}
+	if exclusiveMax && s.Maximum != nil {
+		s.ExclusiveMaximum, s.Maximum = *s.Maximum, nil
+	}
+}
+
+// kindSchema returns the schema for the top-level API type t, with the
+// apiVersion and kind fields constrained to identify it.
+func (b *schemaBuilder) kindSchema(t *types.Type) *jsonSchema {
+	var s jsonSchema
+	if b.structural {
+		s = *b.schemaForType(t)
+	} else {
+		b.schemaForType(t)
+		s = *b.defs[definitionName(t)]
+	}
+	properties := s.Properties
+	s.Properties = make(map[string]*jsonSchema, len(properties))
+	for k, v := range properties {
+		s.Properties[k] = v
+	}
+
+	if b.structural {
+		// CustomResourceDefinitions cannot constrain these fields, the
+		// descriptions are the ones controller-gen uses.
+		s.Properties["apiVersion"] = &jsonSchema{Type: "string", Description: "APIVersion defines the versioned schema of this representation of an object."}
+		s.Properties["kind"] = &jsonSchema{Type: "string", Description: "Kind is a string value representing the REST resource this object represents."}
+		return &s
+	}
+	s.Properties["apiVersion"] = &jsonSchema{Type: "string", Const: apiGroupForType(t, b.typePkgMap)}
+	s.Properties["kind"] = &jsonSchema{Type: "string", Const: t.Name.Name}
+	s.Required = append([]string{"apiVersion", "kind"}, s.Required...)
+	return &s
+}
+
+// kindTypes returns the visible top-level API types in pkgs.
+func kindTypes(pkgs []*apiPackage, config generatorConfig) []*types.Type {
+	var out []*types.Type
+	for _, pkg := range pkgs {
+		for _, t := range visibleTypes(sortTypes(pkg.Types), config) {
+			if isExportedType(t) {
+				out = append(out, t)
+			}
+		}
+	}
+	return out
+}
+
+// jsonSchemaFileName returns the path of the JSON Schema file for the
+// top-level API type t, relative to the output directory.
+func jsonSchemaFileName(t *types.Type, typePkgMap map[*types.Type]*apiPackage) string {
+	pkg := typePkgMap[t]
+	return path.Join(pkg.apiGroup, fmt.Sprintf("%s_%s.json", strings.ToLower(t.Name.Name), pkg.apiVersion))
+}
+
+// jsonSchemaBundleFileName is the name of the file that has the schemas of all
+// top-level API types.
+const jsonSchemaBundleFileName = "bundle.json"
+
+// jsonSchemaBundle returns a schema that accepts any of the top-level API
+// types in pkgs.
+func jsonSchemaBundle(pkgs []*apiPackage, config generatorConfig) *jsonSchema {
+	typePkgMap := extractTypeToPackageMap(pkgs)
+	b := newSchemaBuilder(config, typePkgMap)
+
+	kinds := make(map[string]*jsonSchema)
+	var oneOf []*jsonSchema
+	for _, t := range kindTypes(pkgs, config) {
+		name := definitionName(t)
+		kinds[name] = b.kindSchema(t)
+		oneOf = append(oneOf, &jsonSchema{Ref: "#/definitions/" + name})
+	}
+	for k, v := range kinds {
+		b.defs[k] = v
+	}
+	return &jsonSchema{Schema: jsonSchemaDraft, OneOf: oneOf, Definitions: b.defs}
+}
+
+// renderJSONSchemaBundle writes the bundled schema of all top-level API types.
+func renderJSONSchemaBundle(w io.Writer, pkgs []*apiPackage, config generatorConfig) error {
+	return writeJSON(w, jsonSchemaBundle(pkgs, config))
+}
+
+// renderJSONSchemaFiles returns a JSON Schema file for each top-level API type
+// and the bundle, keyed by their path relative to the output directory.
+func renderJSONSchemaFiles(pkgs []*apiPackage, config generatorConfig) (map[string][]byte, error) {
+	typePkgMap := extractTypeToPackageMap(pkgs)
+	out := make(map[string][]byte)
+
+	for _, t := range kindTypes(pkgs, config) {
+		b := newSchemaBuilder(config, typePkgMap)
+		s := b.kindSchema(t)
+		// the definition of t is only kept for the fields referring to it
+		name := definitionName(t)
+		if !refersTo(s, "#/definitions/"+name, b.defs, make(map[*jsonSchema]bool)) {
+			delete(b.defs, name)
+		}
+		s.Schema = jsonSchemaDraft
+		s.Title = t.Name.Name
+		if len(b.defs) > 0 {
+			s.Definitions = b.defs
+		}
+		var buf bytes.Buffer
+		if err := writeJSON(&buf, s); err != nil {
+			return nil, err
+		}
+		out[jsonSchemaFileName(t, typePkgMap)] = buf.Bytes()
+	}
+
+	var buf bytes.Buffer
+	if err := renderJSONSchemaBundle(&buf, pkgs, config); err != nil {
+		return nil, err
+	}
+	out[jsonSchemaBundleFileName] = buf.Bytes()
+	return out, nil
+}
+
+// refersTo returns whether schema s or one of the schemas in it has the $ref
+// ref, following the references to defs. seen are the schemas already
+// visited.
+func refersTo(s *jsonSchema, ref string, defs map[string]*jsonSchema, seen map[*jsonSchema]bool) bool {
+	if s == nil || seen[s] {
+		return false
+	}
+	seen[s] = true
+	if s.Ref == ref {
+		return true
+	}
+	if d, ok := defs[strings.TrimPrefix(s.Ref, "#/definitions/")]; ok && refersTo(d, ref, defs, seen) {
+		return true
+	}
+	children := []*jsonSchema{s.Items, s.AdditionalProperties}
+	children = append(children, s.AllOf...)
+	children = append(children, s.AnyOf...)
+	children = append(children, s.OneOf...)
+	for _, v := range s.Properties {
+		children = append(children, v)
+	}
+	for _, v := range children {
+		if refersTo(v, ref, defs, seen) {
+			return true
+		}
+	}
+	return false
+}
+
+// writeJSON writes v as indented JSON.
+func writeJSON(w io.Writer, v interface{}) error {
+	e := json.NewEncoder(w)
+	e.SetEscapeHTML(false)
+	e.SetIndent("", "  ")
+	if err := e.Encode(v); err != nil {
+		return fmt.Errorf("failed to encode json: %w", err)
+	}
+	return nil
+}
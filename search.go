@@ -0,0 +1,156 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"strings"
+
+	"k8s.io/gengo/v2/types"
+)
+
+// searchIndexFileName is the file the search index is saved to, next to the
+// HTML output.
+const searchIndexFileName = "search-index.json"
+
+// searchEntry is a document of the client-side search index. The index is a
+// JSON array of them, which can be loaded into lunr (with "id" as the ref) or
+// added to Pagefind as custom records.
+type searchEntry struct {
+	ID string `json:"id"`
+	// Kind is one of "package", "type", "field" or "enum".
+	Kind  string `json:"kind"`
+	Title string `json:"title"`
+	// Type is the name of the type a field or enum value belongs to.
+	Type    string `json:"type,omitempty"`
+	Package string `json:"package"`
+	// URL is the link to the entry, relative to the index page of the site.
+	URL     string `json:"url"`
+	Content string `json:"content,omitempty"`
+}
+
+// buildSearchIndex returns the search index entries of every package, type,
+// field path and enum value of the given layout.
+func buildSearchIndex(pkgs []*apiPackage, config generatorConfig, layout *siteLayout) ([]searchEntry, error) {
+	references := findTypeReferences(pkgs)
+	link := func(t *types.Type) (string, error) {
+		v, err := layout.linkForType(t, config, "")
+		if err != nil {
+			return "", fmt.Errorf("error getting link for type=%s: %w", t.Name, err)
+		}
+		return v, nil
+	}
+
+	out := []searchEntry{}
+	for _, pkg := range pkgs {
+		out = append(out, searchEntry{
+			ID:      pkg.identifier(),
+			Kind:    "package",
+			Title:   pkg.identifier(),
+			Package: pkg.identifier(),
+			URL:     relativeLink("", layout.packagePage(pkg), pkg.identifier()),
+			Content: commentSummary(pkg.GoPackages[0].DocComments),
+		})
+		for _, t := range visibleTypes(sortTypes(pkg.Types), config) {
+			url, err := link(t)
+			if err != nil {
+				return nil, err
+			}
+			id := anchorIDForLocalType(t, layout.typePkgMap)
+			out = append(out, searchEntry{
+				ID:      id,
+				Kind:    "type",
+				Title:   t.Name.Name,
+				Package: pkg.identifier(),
+				URL:     url,
+				Content: commentSummary(t.CommentLines),
+			})
+			out = append(out, enumSearchEntries(t, pkg, id, url, typeAllowedValues(t, pkg))...)
+
+			// the fields of types that other types refer to are listed
+			// with the paths from those types
+			if !isExportedType(t) && len(typeReferences(t, config, references)) > 0 {
+				continue
+			}
+			for _, f := range walkFields(t, config, layout.typePkgMap) {
+				// the nested fields of top-level API types have a row in
+				// their table of field paths, the others link to the row of
+				// the table of the type declaring them
+				fieldID := fieldAnchorID(t, f.Path, layout.typePkgMap)
+				page, anchor := t, fieldID
+				if !isExportedType(t) || f.Depth == 0 {
+					page, anchor = f.Parent, fieldAnchorID(f.Parent, fieldName(f.Member), layout.typePkgMap)
+				}
+				url, err := link(page)
+				if err != nil {
+					return nil, err
+				}
+				url, _, _ = strings.Cut(url, "#")
+				out = append(out, searchEntry{
+					ID:      fieldID,
+					Kind:    "field",
+					Title:   f.Path,
+					Type:    t.Name.Name,
+					Package: pkg.identifier(),
+					URL:     url + "#" + anchor,
+					Content: commentSummary(f.Member.CommentLines),
+				})
+				// values allowed by the Enum marker of the field
+				values := memberAllowedValues(f.Member, layout.typePkgMap)
+				out = append(out, enumSearchEntries(t, pkg, fieldID, url+"#"+anchor, values)...)
+			}
+		}
+	}
+	return out, nil
+}
+
+// enumSearchEntries returns the entries of the allowed values of the type or
+// field with the given anchor id and URL, listed with type t of pkg.
+func enumSearchEntries(t *types.Type, pkg *apiPackage, id, url string, values []allowedValue) []searchEntry {
+	var out []searchEntry
+	for _, v := range values {
+		var content string
+		if v.Constant != nil {
+			content = commentSummary(v.Constant.CommentLines)
+		}
+		out = append(out, searchEntry{
+			ID:      id + "." + v.Value,
+			Kind:    "enum",
+			Title:   v.Value,
+			Type:    t.Name.Name,
+			Package: pkg.identifier(),
+			URL:     url,
+			Content: content,
+		})
+	}
+	return out
+}
+
+// renderSearchIndex returns the search index of the given layout as JSON.
+func renderSearchIndex(pkgs []*apiPackage, config generatorConfig, layout *siteLayout) ([]byte, error) {
+	entries, err := buildSearchIndex(pkgs, config, layout)
+	if err != nil {
+		return nil, err
+	}
+	var b bytes.Buffer
+	if err := writeJSON(&b, entries); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// embeddedSearchIndex returns the search index of single page output to be
+// embedded in a script element of the page.
+func embeddedSearchIndex(pkgs []*apiPackage, config generatorConfig, layout *siteLayout) (template.JS, error) {
+	entries, err := buildSearchIndex(pkgs, config, layout)
+	if err != nil {
+		return "", err
+	}
+	// json.Marshal escapes "<", so the index cannot close the script element
+	b, err := json.Marshal(entries)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode json: %w", err)
+	}
+	return template.JS(b), nil
+}
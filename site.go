@@ -0,0 +1,191 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"strings"
+
+	"k8s.io/gengo/v2/types"
+)
+
+// pageExtensions are the file extensions of the pages written in multi-page
+// output, for each output format that supports it.
+var pageExtensions = map[string]string{
+	outputFormatHTML:     ".html",
+	outputFormatMarkdown: ".md",
+	outputFormatAsciiDoc: ".adoc",
+	outputFormatHugo:     ".md",
+	outputFormatMDX:      ".mdx",
+	outputFormatMkDocs:   ".md",
+	outputFormatRST:      ".rst",
+}
+
+// siteLayout decides which page each package and local type is rendered on.
+// The zero value (with typePkgMap set) is the layout of single page output,
+// where everything is on the page with the empty path.
+type siteLayout struct {
+	typePkgMap map[*types.Type]*apiPackage
+
+	multiPage   bool
+	ext         string
+	pagePerKind bool
+	// sections lays out the index and package pages as the _index pages of
+	// content sections (e.g. in Hugo), so the pages of top-level API types
+	// are nested in the section of their package.
+	sections bool
+}
+
+func (l *siteLayout) indexPage() string {
+	if !l.multiPage {
+		return ""
+	}
+	if l.sections {
+		return "_index" + l.ext
+	}
+	return "index" + l.ext
+}
+
+func (l *siteLayout) packagePage(p *apiPackage) string {
+	if !l.multiPage {
+		return ""
+	}
+	if l.sections {
+		return path.Join(p.apiGroup, p.apiVersion, "_index"+l.ext)
+	}
+	return path.Join(p.apiGroup, p.apiVersion+l.ext)
+}
+
+// typePage returns the page local type t is rendered on: top-level API types
+// get their own page if pagePerKind is set, other types are on the page of
+// their package.
+func (l *siteLayout) typePage(t *types.Type) string {
+	t = tryDereference(t)
+	pkg := l.typePkgMap[t]
+	if !l.multiPage || pkg == nil {
+		return ""
+	}
+	if l.pagePerKind && isExportedType(t) {
+		return path.Join(pkg.apiGroup, pkg.apiVersion, strings.ToLower(t.Name.Name)+l.ext)
+	}
+	return l.packagePage(pkg)
+}
+
+// typesOnPage filters the types that are rendered on the given page.
+func (l *siteLayout) typesOnPage(in []*types.Type, page string) []*types.Type {
+	var out []*types.Type
+	for _, t := range in {
+		if l.typePage(t) == page {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// linkForType returns the link to type t from the page at path from. Links
+// to local types on other pages are relative to from.
+func (l *siteLayout) linkForType(t *types.Type, c generatorConfig, from string) (string, error) {
+	link, err := linkForType(t, c, l.typePkgMap)
+	if err != nil || !strings.HasPrefix(link, "#") {
+		return link, err
+	}
+	return relativeLink(from, l.typePage(t), strings.TrimPrefix(link, "#")), nil
+}
+
+// relativeLink returns the link to anchor on the page at path to, from the
+// page at path from.
+func relativeLink(from, to, anchor string) string {
+	if from == to {
+		return "#" + anchor
+	}
+	fromDir := strings.Split(path.Dir(from), "/")
+	if path.Dir(from) == "." {
+		fromDir = nil
+	}
+	toParts := strings.Split(to, "/")
+
+	var common int
+	for common < len(fromDir) && common < len(toParts)-1 && fromDir[common] == toParts[common] {
+		common++
+	}
+	rel := strings.Repeat("../", len(fromDir)-common) + strings.Join(toParts[common:], "/")
+	return rel + "#" + anchor
+}
+
+// renderSite renders the given template-based output format as multiple
+// pages: an index page, a page for each API package and, if pagePerKind is
+// set, a page for each top-level API type.
+func renderSite(pkgs []*apiPackage, config generatorConfig, format string, pagePerKind bool) (map[string][]byte, error) {
+	layout := &siteLayout{
+		typePkgMap:  extractTypeToPackageMap(pkgs),
+		multiPage:   true,
+		ext:         pageExtensions[format],
+		pagePerKind: pagePerKind,
+		sections:    format == outputFormatHugo,
+	}
+	commit := gitCommit(config)
+	out := make(map[string][]byte)
+
+	renderPage := func(page, name string, data map[string]interface{}) error {
+		t, err := parseTemplates(format, templateFuncs(pkgs, config, format, layout, page))
+		if err != nil {
+			return err
+		}
+		data["packages"] = pkgs
+		data["config"] = config
+		data["gitCommit"] = commit
+
+		var b bytes.Buffer
+		if err := t.ExecuteTemplate(&b, name, data); err != nil {
+			return fmt.Errorf("template execution error for page %s: %w", page, err)
+		}
+		out[page] = []byte(postProcess(format, b.String()))
+		return nil
+	}
+
+	if err := renderPage(layout.indexPage(), "index", map[string]interface{}{}); err != nil {
+		return nil, err
+	}
+	for i, pkg := range pkgs {
+		if err := renderPage(layout.packagePage(pkg), "packagePage", map[string]interface{}{"package": pkg, "weight": i + 1}); err != nil {
+			return nil, err
+		}
+		if !pagePerKind {
+			continue
+		}
+		for j, t := range kindTypes([]*apiPackage{pkg}, config) {
+			if err := renderPage(layout.typePage(t), "kindPage", map[string]interface{}{"package": pkg, "type": t, "weight": j + 1}); err != nil {
+				return nil, err
+			}
+		}
+	}
+	switch format {
+	case outputFormatHTML:
+		if *flSearchIndex {
+			b, err := renderSearchIndex(pkgs, config, layout)
+			if err != nil {
+				return nil, err
+			}
+			out[searchIndexFileName] = b
+		}
+		if *flSiteURL != "" {
+			b, err := renderSitemap(pkgs, config, layout, "", *flSiteURL)
+			if err != nil {
+				return nil, err
+			}
+			out[sitemapFileName] = b
+			if out[urlManifestFileName], err = renderURLManifest(pkgs, config, layout, "", *flSiteURL); err != nil {
+				return nil, err
+			}
+		}
+	case outputFormatMDX:
+		b, err := docusaurusSidebar(pkgs, config, layout)
+		if err != nil {
+			return nil, err
+		}
+		out[docusaurusSidebarFileName] = b
+	case outputFormatMkDocs:
+		out[mkdocsNavFileName] = mkdocsNav(pkgs, config, layout)
+	}
+	return out, nil
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+const (
+	// sitemapFileName is the file the sitemap of the pages is saved to, next
+	// to the HTML output.
+	sitemapFileName = "sitemap.xml"
+	// urlManifestFileName is the file the URLs of the packages, types and
+	// fields are saved to, next to the HTML output.
+	urlManifestFileName = "url-manifest.json"
+
+	sitemapNamespace = "http://www.sitemaps.org/schemas/sitemap/0.9"
+)
+
+// urlManifestEntry is the location of a package, type or field in the output.
+type urlManifestEntry struct {
+	// Kind is one of "package", "type" or "field".
+	Kind string `json:"kind"`
+	// Page is the path of the page relative to the output directory.
+	Page   string `json:"page"`
+	Anchor string `json:"anchor"`
+	URL    string `json:"url"`
+}
+
+// sitePages returns the paths of the pages of the given layout. page is the
+// path of single page output.
+func sitePages(pkgs []*apiPackage, config generatorConfig, layout *siteLayout, page string) []string {
+	if !layout.multiPage {
+		return []string{page}
+	}
+	out := []string{layout.indexPage()}
+	for _, pkg := range pkgs {
+		out = append(out, layout.packagePage(pkg))
+		if layout.pagePerKind {
+			for _, t := range kindTypes([]*apiPackage{pkg}, config) {
+				out = append(out, layout.typePage(t))
+			}
+		}
+	}
+	return out
+}
+
+// buildURLManifest returns the location of every package, type and field of
+// the given layout, keyed by their anchor. A field is listed both by the row
+// of its type's table and by its path from each top-level API type, with the
+// anchors of fieldAnchorID. page is the path of single page output, and
+// siteURL the URL the output directory is published at.
+func buildURLManifest(pkgs []*apiPackage, config generatorConfig, layout *siteLayout, page, siteURL string) map[string]urlManifestEntry {
+	out := make(map[string]urlManifestEntry)
+	add := func(kind, p, anchor string) {
+		if !layout.multiPage {
+			p = page
+		}
+		out[anchor] = urlManifestEntry{Kind: kind, Page: p, Anchor: anchor, URL: siteURLForPage(siteURL, p) + "#" + anchor}
+	}
+
+	for _, pkg := range pkgs {
+		add("package", layout.packagePage(pkg), strings.Replace(pkg.identifier(), " ", "", -1))
+		for _, t := range visibleTypes(pkg.Types, config) {
+			typePage := layout.typePage(t)
+			add("type", typePage, anchorIDForLocalType(t, layout.typePkgMap))
+			for _, m := range t.Members {
+				if !hiddenMember(m, config) {
+					add("field", typePage, fieldAnchorID(t, fieldName(m), layout.typePkgMap))
+				}
+			}
+			if !isExportedType(t) {
+				continue
+			}
+			for _, f := range walkFields(t, config, layout.typePkgMap) {
+				// the fields of the type itself are the rows of its table
+				if f.Depth > 0 {
+					add("field", typePage, fieldAnchorID(t, f.Path, layout.typePkgMap))
+				}
+			}
+		}
+	}
+	return out
+}
+
+// siteURLForPage returns the URL of the page at path p.
+func siteURLForPage(siteURL, p string) string {
+	return strings.TrimSuffix(siteURL, "/") + "/" + p
+}
+
+// renderURLManifest returns the URL manifest of the given layout as JSON.
+// Its keys are sorted, so that it only changes when the output does.
+func renderURLManifest(pkgs []*apiPackage, config generatorConfig, layout *siteLayout, page, siteURL string) ([]byte, error) {
+	var b bytes.Buffer
+	if err := writeJSON(&b, buildURLManifest(pkgs, config, layout, page, siteURL)); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+type sitemapURLSet struct {
+	XMLName xml.Name     `xml:"urlset"`
+	Xmlns   string       `xml:"xmlns,attr"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+type sitemapURL struct {
+	Loc string `xml:"loc"`
+}
+
+// renderSitemap returns the sitemap of the pages of the given layout. It has
+// no modification times, so that it only changes when the pages do.
+func renderSitemap(pkgs []*apiPackage, config generatorConfig, layout *siteLayout, page, siteURL string) ([]byte, error) {
+	set := sitemapURLSet{Xmlns: sitemapNamespace}
+	for _, p := range sitePages(pkgs, config, layout, page) {
+		set.URLs = append(set.URLs, sitemapURL{Loc: siteURLForPage(siteURL, p)})
+	}
+	b, err := xml.MarshalIndent(set, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode xml: %w", err)
+	}
+	return append([]byte(xml.Header), append(b, '\n')...), nil
+}
@@ -0,0 +1,49 @@
+{{ define "members" -}}
+{{ $type := . -}}
+
+{{ range .Members -}}
+{{ if not (hiddenMember .) -}}
+| [[{{ fieldAnchorID $type (fieldName .) }}]]`+{{ fieldName . }}+`
+| {{ typeLink .Type }}
+a|
+{{- if fieldEmbedded . }} (Members of `+{{ fieldName . }}+` are embedded into this type.)
+{{ end }}
+{{- if isRequiredField $type . }} _(Required)_
+{{ else if isOptionalField $type . }} _(Optional)_
+{{ end }}
+{{- with (memberDefault .) }} _Default:_ {{ tableCell (literal .) }}
+{{ end }}
+{{- with (tableCell (renderComments .CommentLines)) }}
+{{ . }}
+{{ end }}
+{{- with (constraints .) }}
+_Validation:_
+
+{{ range . }}* {{ .Label }}: {{ literal .Value }}
+{{ end }}
+{{ end }}
+{{- with (memberAllowedValues .) }}
+_Allowed values:_ {{ range $i, $v := . }}{{ if $i }}, {{ end }}{{ tableCell (literal $v.Literal) }}{{ end }}
+{{ end }}
+{{- with (celRules .CommentLines) }}
+_Validation rules:_
+
+{{ range . }}* {{ if .Immutable }}*Immutable:* {{ end }}{{ tableCell (text .Summary) }}
++
+.CEL rule
+[%collapsible]
+====
+[source]
+----
+{{ tableCell .Rule }}
+----
+====
+{{ end }}
+{{ end }}
+{{- if eq .Type.Name.Name "ObjectMeta" }}
+Refer to the Kubernetes API documentation for the fields of the `+metadata+` field.
+{{ end }}
+{{ end -}}
+{{ end -}}
+
+{{ end }}
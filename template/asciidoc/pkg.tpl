@@ -0,0 +1,56 @@
+{{ define "packages" -}}
+
+{{ with .packages -}}
+Packages:
+
+{{ range . -}}
+* <<{{ packageAnchorID . }},{{ packageDisplayName . }}>>
+{{ end }}
+{{ end }}
+
+{{ range .packages -}}
+{{ template "package" . }}
+
+'''
+
+{{ end -}}
+
+{{ template "footer" . -}}
+{{ end }}
+
+{{ define "package" -}}
+[id="{{ packageAnchorID . }}"]
+== {{ packageDisplayName . }}
+
+{{ with (index .GoPackages 0) -}}
+{{ with .DocComments -}}
+{{ renderComments . }}
+{{ end }}
+{{ end }}
+
+Resource Types:
+
+{{ range (visibleTypes (sortedTypes .Types)) -}}
+{{ if isExportedType . -}}
+* {{ typeLink . }}
+{{ end -}}
+{{ end }}
+
+{{ with (packageClassDiagram .) -}}
+{{ template "classDiagram" . }}
+{{ end }}
+
+{{ range (typesOnPage (visibleTypes (sortedTypes .Types))) -}}
+{{ template "type" . }}
+{{ end }}
+{{ end }}
+
+{{ define "classDiagram" -}}
+[mermaid]
+....
+{{ . }}....
+{{- end }}
+
+{{ define "footer" -}}
+_Generated with `+gen-crd-api-reference-docs+`{{ with .gitCommit }} on git commit `+{{ . }}+`{{ end }}._
+{{ end }}
@@ -0,0 +1,31 @@
+{{ define "index" -}}
+
+Packages:
+
+{{ range .packages -}}
+* xref:{{ packageLink . }}[{{ packageDisplayName . }}]
+{{ range (visibleTypes (sortedTypes .Types)) -}}
+{{ if isExportedType . -}}
+** {{ typeLink . }}
+{{ end -}}
+{{ end -}}
+{{ end }}
+
+{{ template "footer" . -}}
+{{ end }}
+
+{{ define "packagePage" -}}
+
+{{ template "package" .package }}
+
+{{ template "footer" . -}}
+{{ end }}
+
+{{ define "kindPage" -}}
+
+Package: xref:{{ packageLink .package }}[{{ packageDisplayName .package }}]
+
+{{ template "type" .type }}
+
+{{ template "footer" . -}}
+{{ end }}
@@ -0,0 +1,95 @@
+{{ define "type" -}}
+[id="{{ anchorIDForType . }}"]
+=== {{ .Name.Name }}{{ if eq .Kind "Alias" }} (`+{{ .Underlying }}+` alias){{ end }}
+
+{{ with (typeReferences .) -}}
+_Appears on:_
+{{- range $i, $t := . }}{{ if $i }},{{ end }} {{ typeLink $t }}{{ end }}
+{{ end }}
+
+{{ renderComments .CommentLines }}
+
+{{ with (celRules .CommentLines) -}}
+_Validation rules:_
+
+{{ range . }}* {{ if .Immutable }}*Immutable:* {{ end }}{{ text .Summary }}
++
+.CEL rule
+[%collapsible]
+====
+[source]
+----
+{{ .Rule }}
+----
+====
+{{ end }}
+{{ end }}
+
+{{ with (and (isExportedType .) (exampleYAML .)) -}}
+.Example
+[source,yaml]
+----
+{{ . }}----
+{{ end }}
+
+{{ with (allowedValues .) -}}
+.Allowed values
+[cols="1,3",options="header"]
+|===
+| Value | Description
+
+{{ range . -}}
+| {{ literal .Literal }}
+a|{{ with .Constant }} {{ tableCell (renderComments .CommentLines) }}{{ end }}
+
+{{ end -}}
+|===
+{{ end }}
+
+{{ if .Members -}}
+[cols="1,1,3",options="header"]
+|===
+| Field | Type | Description
+
+{{ if isExportedType . -}}
+| `+apiVersion+`
+| `+string+`
+| `+{{ apiGroup . }}+`
+
+| `+kind+`
+| `+string+`
+| `+{{ .Name.Name }}+`
+
+{{ end -}}
+{{ template "members" . }}
+|===
+{{ end }}
+
+{{ if isExportedType . -}}
+{{ template "fieldPaths" . }}
+{{ with (kindClassDiagram .) -}}
+{{ template "classDiagram" . }}
+{{ end }}
+{{ end }}
+
+{{ end }}
+
+{{ define "fieldPaths" -}}
+{{ $type := . -}}
+{{ with (fieldPaths .) -}}
+.Field paths
+[%collapsible]
+====
+[cols="2,1",options="header"]
+|===
+| Path | Type
+
+{{ range . -}}
+| {{ if .Depth }}[[{{ fieldAnchorID $type .Path }}]]{{ end }}`+{{ .Path }}+`{{ if isRequiredField .Parent .Member }} _(Required)_{{ else if isOptionalField .Parent .Member }} _(Optional)_{{ end }}
+| {{ typeLink .Member.Type }}{{ if .Recursive }} (fields listed above){{ end }}
+
+{{ end -}}
+|===
+====
+{{ end }}
+{{- end }}
@@ -0,0 +1,24 @@
+{{- /*
+    Hugo content pages are the markdown pages, with front matter.
+*/ -}}
+{{ define "pageHeader" -}}
+---
+{{ if .type -}}
+title: {{ yamlString .type.Name.Name }}
+description: {{ yamlString (commentSummary .type.CommentLines) }}
+{{ else if .package -}}
+title: {{ yamlString (packageDisplayName .package) }}
+description: {{ yamlString (commentSummary (index .package.GoPackages 0).DocComments) }}
+{{ else -}}
+title: "API Reference"
+{{ end -}}
+{{ with .weight }}weight: {{ . }}
+{{ end -}}
+{{ with (hugoAliases .package .type) -}}
+aliases:
+{{ range . }}- {{ yamlString . }}
+{{ end -}}
+{{ end -}}
+---
+
+{{ end }}
@@ -0,0 +1,48 @@
+{{ define "packages" -}}
+# API Reference
+
+> Condensed reference of the {{ range $i, $p := .packages }}{{ if $i }}, {{ end }}{{ packageDisplayName $p }}{{ end }} APIs: the fields of each type by path, with their types, enum values, defaults and a one-line description.
+
+{{ range .packages -}}
+## {{ packageDisplayName . }}
+
+{{ with (commentSummary (index .GoPackages 0).DocComments) }}{{ . }}{{ end }}
+
+{{ range (visibleTypes (sortedTypes .Types)) -}}
+{{ if or (isExportedType .) (not (typeReferences .)) -}}
+{{ template "llmsType" . }}
+{{ end -}}
+{{ end -}}
+{{ end -}}
+{{ end }}
+
+{{- /*
+    llmsType lists the fields of a type, including the nested ones. Types that
+    are not top-level API types are only listed on their own if no other type
+    refers to them.
+*/ -}}
+{{ define "llmsType" -}}
+### {{ .Name.Name }}{{ if isExportedType . }} (kind){{ end }}
+
+{{ with (commentSummary .CommentLines) }}{{ . }}{{ end }}
+{{ with (enumValues .) }}
+Values: {{ join . " | " }}
+{{ end }}
+{{- range (celRules .CommentLines) }}
+Rule: {{ .Rule }}{{ with .Message }} ({{ . }}){{ end }}
+{{ end }}
+{{ if isExportedType . -}}
+- apiVersion (string): {{ apiGroup . }}
+- kind (string): {{ .Name.Name }}
+{{ end -}}
+{{ range (fieldPaths .) -}}
+- {{ .Path }} ({{ typeDisplayName .Member.Type }}
+{{- if isRequiredField .Parent .Member }}, required{{ else if isOptionalField .Parent .Member }}, optional{{ end }}
+{{- with (memberEnumValues .Member) }}, enum: {{ join . " | " }}{{ end }}
+{{- with (memberDefault .Member) }}, default: {{ . }}{{ end }}
+{{- range (constraints .Member) }}, {{ .Label }}: {{ .Value }}{{ end }}
+{{- range (celRules .Member.CommentLines) }}, {{ if .Immutable }}immutable, {{ end }}rule: {{ .Rule }}{{ with .Message }} ({{ . }}){{ end }}{{ end }}
+{{- if .Recursive }}, recursive{{ end }})
+{{- with (commentSummary .Member.CommentLines) }}: {{ . }}{{ end }}
+{{ end }}
+{{ end }}
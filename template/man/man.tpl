@@ -0,0 +1,64 @@
+{{ define "manPage" -}}
+.TH "{{ manEscape (upper .name) }}" "5" "" "gen-crd-api-reference-docs" "{{ packageDisplayName .package }} API Reference"
+.SH NAME
+{{ manEscape .name }} \- {{ with (commentSummary .type.CommentLines) }}{{ manEscape . }}{{ else }}{{ .type.Name.Name }} API{{ end }}
+.SH SYNOPSIS
+.nf
+apiVersion: {{ packageDisplayName .package }}
+kind: {{ .type.Name.Name }}
+{{ range (fieldPaths .type) }}{{ if eq .Depth 0 -}}
+{{ manEscape .Path }}: <{{ manEscape (typeDisplayName .Member.Type) }}>
+{{ end }}{{ end -}}
+.fi
+{{ with (manText .type.CommentLines) -}}
+.SH DESCRIPTION
+{{ . }}
+{{ end -}}
+{{ with (celRules .type.CommentLines) -}}
+.SH VALIDATION RULES
+{{ range . }}.TP
+{{ if .Immutable }}Immutable: {{ end }}{{ manEscape .Summary }}
+{{ manEscape .Rule }}
+{{ end }}{{ end -}}
+.SH FIELDS
+{{ range (fieldPaths .type) -}}
+.TP
+\fB{{ manEscape .Path }}\fR <{{ manEscape (typeDisplayName .Member.Type) }}>{{ if isRequiredField .Parent .Member }}, required{{ else if isOptionalField .Parent .Member }}, optional{{ end }}{{ with (memberDefault .Member) }}, default {{ manEscape . }}{{ end }}
+{{ with (manText .Member.CommentLines) }}{{ . }}
+{{ end -}}
+{{ with (constraints .Member) }}.sp
+Validation:
+{{ range $i, $c := . }}{{ if $i }},
+{{ end }}{{ manEscape $c.Label }}: {{ manEscape $c.Value }}{{ end }}
+{{ end -}}
+{{ with (memberAllowedValues .Member) }}.sp
+Allowed values: {{ range $i, $v := . }}{{ if $i }}, {{ end }}{{ manEscape $v.Literal }}{{ end }}
+{{ end -}}
+{{ with (celRules .Member.CommentLines) }}.sp
+Validation rules:
+{{ range . }}.br
+{{ if .Immutable }}Immutable: {{ end }}{{ manEscape .Summary }} (rule: {{ manEscape .Rule }})
+{{ end }}{{ end -}}
+{{ if .Recursive }}.sp
+The fields of this type are listed above.
+{{ end -}}
+{{ end -}}
+{{ with (exampleYAML .type) -}}
+.SH EXAMPLE
+.nf
+{{ manEscape . -}}
+.fi
+{{ end -}}
+{{ $type := .type -}}
+{{ with (kindTypes .package) -}}
+{{ if gt (len .) 1 -}}
+.SH SEE ALSO
+{{ range . }}{{ if ne . $type }}.BR {{ manEscape (manPageName .) }} (5)
+{{ end }}{{ end -}}
+{{ end -}}
+{{ end -}}
+{{ with .gitCommit -}}
+.SH NOTES
+Generated with gen-crd-api-reference-docs on git commit {{ . }}.
+{{ end -}}
+{{ end }}
@@ -0,0 +1,18 @@
+{{ define "members" -}}
+{{ $type := . -}}
+
+{{ range .Members -}}
+{{ if not (hiddenMember .) -}}
+| {{ anchor (fieldAnchorID $type (fieldName .)) }}`{{ fieldName . }}` | {{ if linkForType .Type }}[`{{ typeDisplayName .Type }}`]({{ linkForType .Type }}){{ else }}`{{ typeDisplayName .Type }}`{{ end }} |
+{{- if fieldEmbedded . }} (Members of `{{ fieldName . }}` are embedded into this type.){{ end }}
+{{- if isRequiredField $type . }} _(Required)_{{ else if isOptionalField $type . }} _(Optional)_{{ end }}
+{{- with (memberDefault .) }} _Default:_ {{ tableCell (literal .) }}{{ end }}
+{{- with (tableCell (renderComments .CommentLines)) }} {{ . }}{{ end }}
+{{- with (constraints .) }} _Validation:_ {{ range $i, $c := . }}{{ if $i }}, {{ end }}{{ $c.Label }}: {{ tableCell (literal $c.Value) }}{{ end }}{{ end }}
+{{- with (memberAllowedValues .) }} _Allowed values:_ {{ range $i, $v := . }}{{ if $i }}, {{ end }}{{ tableCell (literal $v.Literal) }}{{ end }}{{ end }}
+{{- with (celRules .CommentLines) }} _Validation rules:_ {{ range $i, $r := . }}{{ if $i }}; {{ end }}{{ if $r.Immutable }}**Immutable:** {{ end }}{{ tableCell (text $r.Summary) }} {{ tableCell (collapsible "CEL rule" (literal $r.Rule)) }}{{ end }}{{ end }}
+{{- if eq .Type.Name.Name "ObjectMeta" }} Refer to the Kubernetes API documentation for the fields of the `metadata` field.{{ end }} |
+{{ end -}}
+{{ end -}}
+
+{{ end }}
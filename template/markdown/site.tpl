@@ -0,0 +1,36 @@
+{{ define "index" -}}
+{{ template "pageHeader" . -}}
+Packages:
+
+{{ range .packages -}}
+- [{{ packageDisplayName . }}]({{ packageLink . }})
+{{ range (visibleTypes (sortedTypes .Types)) -}}
+{{ if isExportedType . }}  - [{{ typeDisplayName . }}]({{ linkForType . }})
+{{ end -}}
+{{ end -}}
+{{ end }}
+
+{{ template "footer" . -}}
+{{ end }}
+
+{{ define "packagePage" -}}
+{{ template "pageHeader" . -}}
+{{ template "package" .package }}
+
+{{ template "footer" . -}}
+{{ end }}
+
+{{ define "kindPage" -}}
+{{ template "pageHeader" . -}}
+Package: [{{ packageDisplayName .package }}]({{ packageLink .package }})
+
+{{ template "type" .type }}
+
+{{ template "footer" . -}}
+{{ end }}
+
+{{- /*
+    pageHeader is written at the top of each page, e.g. to add front matter
+    for static site generators.
+*/ -}}
+{{ define "pageHeader" }}{{ end }}
@@ -0,0 +1,72 @@
+{{ define "type" -}}
+{{ if eq .Kind "Alias" -}}
+{{ heading 3 (printf "%s (`%v` alias)" .Name.Name .Underlying) (anchorIDForType .) }}
+{{- else -}}
+{{ heading 3 .Name.Name (anchorIDForType .) }}
+{{- end }}
+
+{{ with (typeReferences .) -}}
+_Appears on:_
+{{- range $i, $t := . }}{{ if $i }},{{ end }} [{{ typeDisplayName $t }}]({{ linkForType $t }}){{ end }}
+{{ end }}
+
+{{ renderComments .CommentLines }}
+
+{{ with (celRules .CommentLines) -}}
+_Validation rules:_
+
+{{ range . -}}
+- {{ if .Immutable }}**Immutable:** {{ end }}{{ text .Summary }}
+
+{{ indent 2 (collapsible "CEL rule" (printf "```cel\n%s\n```" .Rule)) }}
+{{ end }}
+{{ end }}
+
+{{ with (and (isExportedType .) (exampleYAML .)) -}}
+_Example:_
+
+```yaml
+{{ . }}```
+{{ end }}
+
+{{ with (allowedValues .) -}}
+_Allowed values:_
+
+| Value | Description |
+| --- | --- |
+{{ range . -}}
+| {{ tableCell (literal .Literal) }} |{{ with .Constant }} {{ tableCell (renderComments .CommentLines) }}{{ end }} |
+{{ end }}
+{{ end }}
+
+{{ if .Members -}}
+| Field | Type | Description |
+| --- | --- | --- |
+{{ if isExportedType . -}}
+| `apiVersion` | `string` | `{{ apiGroup . }}` |
+| `kind` | `string` | `{{ .Name.Name }}` |
+{{ end -}}
+{{ template "members" . }}
+{{ end }}
+
+{{ if isExportedType . -}}
+{{ template "fieldPaths" . }}
+{{ with (kindClassDiagram .) -}}
+{{ template "classDiagram" . }}
+{{ end }}
+{{ end }}
+
+{{ end }}
+
+{{ define "fieldPaths" -}}
+{{ $type := . -}}
+{{ with (fieldPaths .) -}}
+_Field paths:_
+
+| Path | Type |
+| --- | --- |
+{{ range . -}}
+| {{ if .Depth }}{{ anchor (fieldAnchorID $type .Path) }}{{ end }}`{{ .Path }}`{{ if isRequiredField .Parent .Member }} _(Required)_{{ else if isOptionalField .Parent .Member }} _(Optional)_{{ end }} | {{ if linkForType .Member.Type }}[`{{ typeDisplayName .Member.Type }}`]({{ linkForType .Member.Type }}){{ else }}`{{ typeDisplayName .Member.Type }}`{{ end }}{{ if .Recursive }} (fields listed above){{ end }} |
+{{ end }}
+{{ end }}
+{{- end }}
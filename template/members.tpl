@@ -1,10 +1,17 @@
 {{ define "members" }}
 
+{{ $type := . }}
+{{ $parent := $type }}
+{{ if isNested $type }}{{ $parent = $type.Type }}{{ end }}
 {{ range .Members }}
 {{ if not (hiddenMember .)}}
-<tr>
+<tr{{ if not (isNested $type) }} id="{{ fieldAnchorID $type (fieldName .) }}"{{ end }}>
     <td>
-        <code>{{ fieldName . }}</code><br/>
+        <code>{{ fieldName . }}</code>
+        {{- if not (isNested $type) -}}
+            <a class="permalink" href="{{ print "#" (fieldAnchorID $type (fieldName .)) }}" title="Permalink to this field">#</a>
+        {{- end -}}
+        <br/>
         <em>
             {{ if linkForType .Type }}
                 <a href="{{ linkForType .Type}}">
@@ -22,12 +29,38 @@
             </p>
         {{ end}}
 
-        {{ if isOptionalMember .}}
+        {{ if isRequiredField $parent . }}
+            <em>(Required)</em>
+        {{ else if isOptionalField $parent . }}
             <em>(Optional)</em>
         {{ end }}
 
+        {{ with (memberDefault .) }}
+            <p><em>Default:</em> <code>{{ . }}</code></p>
+        {{ end }}
+
         {{ safe (renderComments .CommentLines) }}
 
+        {{ with (constraints .) }}
+            <p><em>Validation:</em></p>
+            <ul>
+                {{ range . }}
+                <li>{{ .Label }}: <code>{{ .Value }}</code></li>
+                {{ end }}
+            </ul>
+        {{ end }}
+
+        {{ with (memberAllowedValues .) }}
+            <p>
+                <em>Allowed values:</em>
+                {{ range $i, $v := . }}{{ if $i }}, {{ end }}<code>{{ $v.Literal }}</code>{{ end }}
+            </p>
+        {{ end }}
+
+        {{ with (celRules .CommentLines) }}
+            {{ template "validationRules" . }}
+        {{ end }}
+
     {{ if and (eq (.Type.Name.Name) "ObjectMeta") }}
         Refer to the Kubernetes API documentation for the fields of the
         <code>metadata</code> field.
@@ -37,7 +70,7 @@
         <br/>
         <br/>
         <table>
-            {{ template "members" .Type }}
+            {{ template "members" (nested .Type) }}
         </table>
     {{ end }}
     </td>
@@ -46,3 +79,10 @@
 {{ end }}
 
 {{ end }}
+
+{{ define "permalinkStyle" }}
+<style>
+    .permalink { margin-left: 0.25em; text-decoration: none; visibility: hidden; }
+    tr:hover .permalink { visibility: visible; }
+</style>
+{{ end }}
@@ -0,0 +1,49 @@
+{{ define "members" -}}
+{{ $type := . -}}
+
+{{ range .Members -}}
+{{ if not (hiddenMember .) -}}
+- {{ anchor (fieldAnchorID $type (fieldName .)) }}`{{ fieldName . }}` ({{ if linkForType .Type }}[`{{ typeDisplayName .Type }}`]({{ linkForType .Type }}){{ else }}`{{ typeDisplayName .Type }}`{{ end }})
+
+{{ if fieldEmbedded . }}    Members of `{{ fieldName . }}` are embedded into this type.
+
+{{ end -}}
+{{ if isRequiredField $type . }}    !!! note "Required"
+        This field is required.
+
+{{ else if isOptionalField $type . }}    !!! note "Optional"
+        This field is optional.
+
+{{ end -}}
+{{ with (memberDefault .) }}    Default: {{ literal . }}
+
+{{ end -}}
+{{ with (deprecationNotice .CommentLines) }}    !!! warning "Deprecated"
+{{ indent 8 . }}
+
+{{ end -}}
+{{ with (renderComments .CommentLines) }}{{ indent 4 . }}
+
+{{ end -}}
+{{ with (constraints .) }}    !!! info "Validation"
+{{ range . }}        - {{ .Label }}: {{ literal .Value }}
+{{ end }}
+{{ end -}}
+{{ with (memberAllowedValues .) }}    Allowed values: {{ range $i, $v := . }}{{ if $i }}, {{ end }}{{ literal $v.Literal }}{{ end }}
+
+{{ end -}}
+{{ range (celRules .CommentLines) }}    {{ if .Immutable }}**Immutable:** {{ end }}{{ text .Summary }}
+
+    ??? info "CEL rule"
+        ```cel
+        {{ .Rule }}
+        ```
+
+{{ end -}}
+{{ if eq .Type.Name.Name "ObjectMeta" }}    Refer to the Kubernetes API documentation for the fields of the `metadata` field.
+
+{{ end -}}
+{{ end -}}
+{{ end -}}
+
+{{ end }}
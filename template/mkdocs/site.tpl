@@ -0,0 +1,17 @@
+{{- /*
+    MkDocs pages are the markdown pages, with front matter.
+*/ -}}
+{{ define "pageHeader" -}}
+---
+{{ if .type -}}
+title: {{ yamlString .type.Name.Name }}
+description: {{ yamlString (commentSummary .type.CommentLines) }}
+{{ else if .package -}}
+title: {{ yamlString (packageDisplayName .package) }}
+description: {{ yamlString (commentSummary (index .package.GoPackages 0).DocComments) }}
+{{ else -}}
+title: "API Reference"
+{{ end -}}
+---
+
+{{ end }}
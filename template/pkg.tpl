@@ -1,5 +1,11 @@
 {{ define "packages" }}
 
+{{ template "permalinkStyle" }}
+
+{{ with .searchIndex }}
+    {{ template "searchBox" . }}
+{{ end }}
+
 {{ with .packages}}
 <p>Packages:</p>
 <ul>
@@ -12,6 +18,15 @@
 {{ end}}
 
 {{ range .packages }}
+    {{ template "package" . }}
+    <hr/>
+{{ end }}
+
+{{ template "footer" . }}
+
+{{ end }}
+
+{{ define "package" }}
     <h2 id="{{- packageAnchorID . -}}">
         {{- packageDisplayName . -}}
     </h2>
@@ -35,15 +50,24 @@
     {{- end -}}
     </ul>
 
-    {{ range (visibleTypes (sortedTypes .Types))}}
+    {{ with (packageClassDiagram .) }}
+        {{ template "classDiagram" . }}
+    {{ end }}
+
+    {{ range (typesOnPage (visibleTypes (sortedTypes .Types)))}}
         {{ template "type" .  }}
     {{ end }}
-    <hr/>
 {{ end }}
 
+{{ define "classDiagram" }}
+<pre class="mermaid">
+{{ . }}
+</pre>
+{{ end }}
+
+{{ define "footer" }}
 <p><em>
     Generated with <code>gen-crd-api-reference-docs</code>
     {{ with .gitCommit }} on git commit <code>{{ . }}</code>{{end}}.
 </em></p>
-
 {{ end }}
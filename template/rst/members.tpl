@@ -0,0 +1,45 @@
+{{ define "members" -}}
+{{ $type := . -}}
+{{ range .Members -}}
+{{ if not (hiddenMember .) }}   * - .. _{{ fieldAnchorID $type (fieldName .) }}:
+
+       ``{{ fieldName . }}``
+     - {{ typeLink .Type }}
+     - {{ if fieldEmbedded . }}(Members of ``{{ fieldName . }}`` are embedded into this type.)
+
+       {{ end }}{{ if isRequiredField $type . }}*(Required)*
+
+       {{ else if isOptionalField $type . }}*(Optional)*
+
+       {{ end }}{{ with (memberDefault .) }}*Default:* {{ tableCell (literal .) }}
+
+       {{ end }}{{ tableCell (renderComments .CommentLines) }}
+{{- with (constraints .) }}
+
+       *Validation:*
+
+{{ range . }}       - {{ .Label }}: {{ literal .Value }}
+{{ end }}
+{{- end }}
+{{- with (memberAllowedValues .) }}
+
+       *Allowed values:* {{ range $i, $v := . }}{{ if $i }}, {{ end }}{{ tableCell (literal $v.Literal) }}{{ end }}
+{{- end }}
+{{- with (celRules .CommentLines) }}
+
+       *Validation rules:*
+{{ range . }}
+       - {{ if .Immutable }}**Immutable:** {{ end }}{{ tableCell (text .Summary) }}
+
+         .. code-block:: text
+
+            {{ .Rule }}
+{{ end }}
+{{- end }}
+{{- if eq .Type.Name.Name "ObjectMeta" }}
+
+       Refer to the Kubernetes API documentation for the fields of the ``metadata`` field.
+{{- end }}
+{{ end -}}
+{{ end -}}
+{{ end }}
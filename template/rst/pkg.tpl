@@ -0,0 +1,55 @@
+{{ define "packages" -}}
+
+{{ with .packages -}}
+Packages:
+
+{{ range . -}}
+- :ref:`{{ packageDisplayName . }} <{{ packageAnchorID . }}>`
+{{ end }}
+{{ end }}
+
+{{ range .packages -}}
+{{ template "package" . }}
+
+----
+
+{{ end -}}
+
+{{ template "footer" . -}}
+{{ end }}
+
+{{ define "package" -}}
+{{ heading 2 (packageDisplayName .) (packageAnchorID .) }}
+
+{{ with (index .GoPackages 0) -}}
+{{ with .DocComments -}}
+{{ renderComments . }}
+{{ end }}
+{{ end }}
+
+Resource Types:
+
+{{ range (visibleTypes (sortedTypes .Types)) -}}
+{{ if isExportedType . -}}
+- {{ typeLink . }}
+{{ end -}}
+{{ end }}
+
+{{ with (packageClassDiagram .) -}}
+{{ template "classDiagram" . }}
+{{ end }}
+
+{{ range (typesOnPage (visibleTypes (sortedTypes .Types))) -}}
+{{ template "type" . }}
+{{ end }}
+{{ end }}
+
+{{ define "classDiagram" -}}
+.. mermaid::
+
+{{ indent 3 . }}
+{{- end }}
+
+{{ define "footer" -}}
+Generated with ``gen-crd-api-reference-docs``{{ with .gitCommit }} on git commit ``{{ . }}``{{ end }}.
+{{ end }}
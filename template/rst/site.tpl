@@ -0,0 +1,33 @@
+{{ define "index" -}}
+{{ heading 1 "API Reference" "" }}
+
+.. toctree::
+   :maxdepth: 2
+
+{{ range .packages }}   {{ packageDocument . }}
+{{ end }}
+
+{{ template "footer" . -}}
+{{ end }}
+
+{{ define "packagePage" -}}
+{{ template "package" .package }}
+
+{{ with (kindDocuments .package) -}}
+.. toctree::
+   :hidden:
+
+{{ range . }}   {{ . }}
+{{ end }}
+{{ end }}
+
+{{ template "footer" . -}}
+{{ end }}
+
+{{ define "kindPage" -}}
+Package: :ref:`{{ packageDisplayName .package }} <{{ packageAnchorID .package }}>`
+
+{{ template "type" .type }}
+
+{{ template "footer" . -}}
+{{ end }}
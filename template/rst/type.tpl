@@ -0,0 +1,91 @@
+{{ define "type" -}}
+{{ if eq .Kind "Alias" -}}
+{{ heading 3 (printf "%s (``%v`` alias)" .Name.Name .Underlying) (anchorIDForType .) }}
+{{- else -}}
+{{ heading 3 .Name.Name (anchorIDForType .) }}
+{{- end }}
+
+{{ with (typeReferences .) -}}
+*Appears on:*
+{{- range $i, $t := . }}{{ if $i }},{{ end }} {{ typeLink $t }}{{ end }}
+{{ end }}
+
+{{ renderComments .CommentLines }}
+
+{{ with (celRules .CommentLines) -}}
+*Validation rules:*
+
+{{ range . }}- {{ if .Immutable }}**Immutable:** {{ end }}{{ text .Summary }}
+
+  .. code-block:: text
+
+     {{ .Rule }}
+
+{{ end }}
+{{ end }}
+
+{{ with (and (isExportedType .) (exampleYAML .)) -}}
+*Example:*
+
+.. code-block:: yaml
+
+{{ indent 3 . }}
+{{ end }}
+
+{{ with (allowedValues .) -}}
+.. list-table:: Allowed values
+   :header-rows: 1
+   :widths: 25 75
+
+   * - Value
+     - Description
+{{ range . }}   * - {{ literal .Literal }}
+     - {{ with .Constant }}{{ tableCell (renderComments .CommentLines) }}{{ end }}
+{{ end }}
+{{ end }}
+
+{{ if .Members -}}
+.. list-table::
+   :header-rows: 1
+   :widths: 20 20 60
+
+   * - Field
+     - Type
+     - Description
+{{ if isExportedType . }}   * - ``apiVersion``
+     - ``string``
+     - ``{{ apiGroup . }}``
+   * - ``kind``
+     - ``string``
+     - ``{{ .Name.Name }}``
+{{ end -}}
+{{ template "members" . }}
+{{ end }}
+
+{{ if isExportedType . -}}
+{{ template "fieldPaths" . }}
+{{ with (kindClassDiagram .) -}}
+{{ template "classDiagram" . }}
+{{ end }}
+{{ end }}
+
+{{ end }}
+
+{{ define "fieldPaths" -}}
+{{ $type := . -}}
+{{ with (fieldPaths .) -}}
+*Field paths:*
+
+.. list-table::
+   :header-rows: 1
+   :widths: 60 40
+
+   * - Path
+     - Type
+{{ range . }}   * - {{ if .Depth }}.. _{{ fieldAnchorID $type .Path }}:
+
+       {{ end }}``{{ .Path }}``{{ if isRequiredField .Parent .Member }} *(Required)*{{ else if isOptionalField .Parent .Member }} *(Optional)*{{ end }}
+     - {{ typeLink .Member.Type }}{{ if .Recursive }} (fields listed above){{ end }}
+{{ end }}
+{{ end }}
+{{- end }}
@@ -0,0 +1,32 @@
+{{ define "searchBox" }}
+<div class="search">
+    <input type="search" id="search-input" placeholder="Search types, fields and values" autocomplete="off" aria-label="Search types, fields and values">
+    <ul id="search-results"></ul>
+</div>
+<script type="application/json" id="search-index">{{ . }}</script>
+<script>
+(function() {
+    var index = JSON.parse(document.getElementById("search-index").textContent);
+    var input = document.getElementById("search-input");
+    var results = document.getElementById("search-results");
+    input.addEventListener("input", function() {
+        var q = input.value.trim().toLowerCase();
+        results.innerHTML = "";
+        if (!q) {
+            return;
+        }
+        index.filter(function(e) {
+            return e.title.toLowerCase().indexOf(q) >= 0 || e.id.toLowerCase().indexOf(q) >= 0;
+        }).slice(0, 20).forEach(function(e) {
+            var a = document.createElement("a");
+            a.href = e.url;
+            a.textContent = e.title;
+            var li = document.createElement("li");
+            li.appendChild(a);
+            li.appendChild(document.createTextNode(" (" + e.kind + (e.type ? " of " + e.type : "") + ", " + e.package + ")"));
+            results.appendChild(li);
+        });
+    });
+})();
+</script>
+{{ end }}
@@ -0,0 +1,49 @@
+{{ define "index" }}
+
+{{ template "permalinkStyle" }}
+
+<p>Packages:</p>
+<ul>
+    {{ range .packages }}
+    <li>
+        <a href="{{ packageLink . }}">{{ packageDisplayName . }}</a>
+        <ul>
+        {{- range (visibleTypes (sortedTypes .Types)) -}}
+            {{ if isExportedType . -}}
+            <li>
+                <a href="{{ linkForType . }}">{{ typeDisplayName . }}</a>
+            </li>
+            {{- end }}
+        {{- end -}}
+        </ul>
+    </li>
+    {{ end }}
+</ul>
+
+{{ template "footer" . }}
+
+{{ end }}
+
+{{ define "packagePage" }}
+
+{{ template "permalinkStyle" }}
+
+{{ template "package" .package }}
+
+{{ template "footer" . }}
+
+{{ end }}
+
+{{ define "kindPage" }}
+
+{{ template "permalinkStyle" }}
+
+<p>
+    Package: <a href="{{ packageLink .package }}">{{ packageDisplayName .package }}</a>
+</p>
+
+{{ template "type" .type }}
+
+{{ template "footer" . }}
+
+{{ end }}
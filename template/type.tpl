@@ -21,7 +21,17 @@
     {{ safe (renderComments .CommentLines) }}
 </div>
 
-{{ with (constantsOfType .) }}
+{{ with (celRules .CommentLines) }}
+    {{ template "validationRules" . }}
+{{ end }}
+
+{{ with (and (isExportedType .) (exampleYAML .)) }}
+<p><em>Example:</em></p>
+<pre><code class="language-yaml">{{ . }}</code></pre>
+{{ end }}
+
+{{ with (allowedValues .) }}
+<p><em>Allowed values:</em></p>
 <table>
     <thead>
         <tr>
@@ -37,8 +47,8 @@
             add one to the display name as well to make the contents
             of the two cells align evenly.
         */ -}}
-        <td><p>{{ typeDisplayName . }}</p></td>
-        <td>{{ safe (renderComments .CommentLines) }}</td>
+        <td><p>{{ .Literal }}</p></td>
+        <td>{{ with .Constant }}{{ safe (renderComments .CommentLines) }}{{ end }}</td>
       </tr>
       {{- end -}}
     </tbody>
@@ -78,4 +88,73 @@
 </table>
 {{ end }}
 
+{{ if isExportedType . }}
+    {{ template "fieldPaths" . }}
+    {{ with (kindClassDiagram .) }}
+        {{ template "classDiagram" . }}
+    {{ end }}
+{{ end }}
+
+{{ end }}
+
+{{ define "validationRules" }}
+<p><em>Validation rules:</em></p>
+<ul>
+    {{ range . }}
+    <li>
+        {{ if .Immutable }}<strong>Immutable:</strong>{{ end }}
+        {{ .Summary }}
+        <details>
+            <summary>CEL rule</summary>
+            <pre><code>{{ .Rule }}</code></pre>
+        </details>
+    </li>
+    {{ end }}
+</ul>
+{{ end }}
+
+{{ define "fieldPaths" }}
+{{ $type := . }}
+{{ with (fieldPaths .) }}
+<details>
+    <summary>Field paths</summary>
+    <table>
+        <thead>
+            <tr>
+                <th>Path</th>
+                <th>Type</th>
+            </tr>
+        </thead>
+        <tbody>
+            {{ range . }}
+            {{/* the fields of the type itself link to the rows of its table */}}
+            {{ $anchor := fieldAnchorID $type .Path }}
+            {{ if eq .Depth 0 }}{{ $anchor = fieldAnchorID .Parent (fieldName .Member) }}{{ end }}
+            <tr{{ if ne .Depth 0 }} id="{{ $anchor }}"{{ end }}>
+                <td>
+                    <code>{{ .Path }}</code>
+                    {{- /**/ -}}
+                    <a class="permalink" href="{{ print "#" $anchor }}" title="Permalink to this field">#</a>
+                    {{ if isRequiredField .Parent .Member }}
+                        <em>(Required)</em>
+                    {{ else if isOptionalField .Parent .Member }}
+                        <em>(Optional)</em>
+                    {{ end }}
+                </td>
+                <td>
+                    {{ if linkForType .Member.Type }}
+                        <a href="{{ linkForType .Member.Type }}">{{ typeDisplayName .Member.Type }}</a>
+                    {{ else }}
+                        {{ typeDisplayName .Member.Type }}
+                    {{ end }}
+                    {{ if .Recursive }}
+                        (fields listed above)
+                    {{ end }}
+                </td>
+            </tr>
+            {{ end }}
+        </tbody>
+    </table>
+</details>
+{{ end }}
 {{ end }}